@@ -0,0 +1,84 @@
+package modular
+
+import (
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+func (lr *loggerRoot) Alias(from, to string) error {
+	separator := lr.GetSeparator()
+	if _, err := splitModuleName(from, separator); err != nil {
+		return err
+	}
+	if _, err := splitModuleName(to, separator); err != nil {
+		return err
+	}
+	// Targets within the source would be resolved again when looking up the created modules
+	if to == from || strings.HasPrefix(to, from+separator) {
+		return ErrInvalidAlias
+	}
+
+	lr.aliasesMutex.Lock()
+	defer lr.aliasesMutex.Unlock()
+
+	if lr.aliases == nil {
+		lr.aliases = make(map[string]string)
+	}
+	lr.aliases[from] = to
+	return nil
+}
+
+func (lr *loggerRoot) RemoveAlias(from string) {
+	lr.aliasesMutex.Lock()
+	defer lr.aliasesMutex.Unlock()
+
+	delete(lr.aliases, from)
+}
+
+func (lr *loggerRoot) GetAliases() map[string]string {
+	lr.aliasesMutex.Lock()
+	defer lr.aliasesMutex.Unlock()
+
+	aliases := make(map[string]string, len(lr.aliases))
+	for from, to := range lr.aliases {
+		aliases[from] = to
+	}
+	return aliases
+}
+
+// resolveAlias rewrites the given module name according to the alias matching the longest prefix.
+// Aliases are applied once, so the target of an alias is not subject to further aliasing.
+func (lr *loggerRoot) resolveAlias(moduleName string) string {
+	separator := lr.GetSeparator()
+
+	lr.aliasesMutex.Lock()
+	defer lr.aliasesMutex.Unlock()
+
+	matchedFrom := ""
+	for from := range lr.aliases {
+		if len(from) > len(matchedFrom) && (moduleName == from || strings.HasPrefix(moduleName, from+separator)) {
+			matchedFrom = from
+		}
+	}
+	if matchedFrom == "" {
+		return moduleName
+	}
+	return lr.aliases[matchedFrom] + moduleName[len(matchedFrom):]
+}
+
+func (lr *loggerRoot) GetChild(moduleName string) (ModuleLogger, error) {
+	return lr.loggerModule.GetChild(lr.resolveAlias(moduleName))
+}
+
+func (lr *loggerRoot) CreateChild(moduleName string, defaultLevel logrus.Level) (ModuleLogger, error) {
+	return lr.loggerModule.CreateChild(lr.resolveAlias(moduleName), defaultLevel)
+}
+
+func (lr *loggerRoot) GetOrCreateChild(moduleName string, defaultLevel logrus.Level) ModuleLogger {
+	return lr.loggerModule.GetOrCreateChild(lr.resolveAlias(moduleName), defaultLevel)
+}
+
+func (lr *loggerRoot) RemoveChild(moduleName string) error {
+	return lr.loggerModule.RemoveChild(lr.resolveAlias(moduleName))
+}
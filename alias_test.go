@@ -0,0 +1,80 @@
+package modular
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerRoot_Alias(t *testing.T) {
+	rl := NewRootLogger(logrus.New())
+	require.NoError(t, rl.Alias("grpc", "third_party.grpc"))
+	require.NoError(t, rl.Alias("grpc.transport", "third_party.transport"))
+	require.NoError(t, rl.Alias("redis", "third_party.redis"))
+	require.EqualValues(t, map[string]string{
+		"grpc":           "third_party.grpc",
+		"grpc.transport": "third_party.transport",
+		"redis":          "third_party.redis",
+	}, rl.GetAliases())
+
+	grpc := rl.GetOrCreateChild("grpc", logrus.InfoLevel)
+	require.EqualValues(t, "third_party.grpc", grpc.GetModuleName())
+
+	child, err := rl.CreateChild("grpc.server", logrus.InfoLevel)
+	require.NoError(t, err)
+	require.EqualValues(t, "third_party.grpc.server", child.GetModuleName())
+
+	// Longest prefix wins
+	child = rl.GetOrCreateChild("grpc.transport.http2", logrus.InfoLevel)
+	require.EqualValues(t, "third_party.transport.http2", child.GetModuleName())
+
+	// Segments are matched as a whole
+	child = rl.GetOrCreateChild("grpcx", logrus.InfoLevel)
+	require.EqualValues(t, "grpcx", child.GetModuleName())
+
+	child, err = rl.GetChild("grpc")
+	require.NoError(t, err)
+	require.EqualValues(t, grpc, child)
+
+	// Levels can be controlled for the whole subtree
+	thirdParty, err := rl.GetChild("third_party")
+	require.NoError(t, err)
+	thirdParty.SetLevel(logrus.ErrorLevel)
+	require.EqualValues(t, logrus.ErrorLevel, grpc.GetLevel())
+
+	rl.RemoveAlias("grpc")
+	child = rl.GetOrCreateChild("grpc", logrus.InfoLevel)
+	require.EqualValues(t, "grpc", child.GetModuleName())
+
+	require.True(t, errors.Is(rl.Alias("", "x"), ErrInvalidModuleName))
+	require.True(t, errors.Is(rl.Alias("x", "a..b"), ErrInvalidModuleName))
+}
+
+func TestLoggerRoot_RemoveChild_Alias(t *testing.T) {
+	rl := NewRootLogger(logrus.New())
+	require.NoError(t, rl.Alias("grpc", "third_party.grpc"))
+	rl.GetOrCreateChild("grpc.server", logrus.InfoLevel)
+
+	require.NoError(t, rl.RemoveChild("grpc"))
+	_, err := rl.GetChild("third_party.grpc")
+	require.True(t, errors.Is(err, ErrChildNotFound))
+	_, err = rl.GetChild("third_party")
+	require.NoError(t, err)
+}
+
+func TestLoggerRoot_Alias_WithinSource(t *testing.T) {
+	rl := NewRootLogger(logrus.New())
+	require.EqualValues(t, ErrInvalidAlias, rl.Alias("a", "a"))
+	require.EqualValues(t, ErrInvalidAlias, rl.Alias("a", "a.b"))
+	require.Empty(t, rl.GetAliases())
+
+	// Targets sharing a prefix only are valid
+	require.NoError(t, rl.Alias("a", "ab.a"))
+	child := rl.GetOrCreateChild("a", logrus.InfoLevel)
+	require.EqualValues(t, "ab.a", child.GetModuleName())
+	found, err := rl.GetChild("ab.a")
+	require.NoError(t, err)
+	require.EqualValues(t, child, found)
+}
@@ -0,0 +1,129 @@
+package modular
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// AuditModuleName defines the name of the module audit records are logged through, joined by DefaultSeparator.
+	// Root loggers using another separator join its segments by their own separator, i.e. "logging/audit".
+	AuditModuleName = "logging.audit"
+	// DefaultAuditHistorySize defines the number of audit records kept by default
+	DefaultAuditHistorySize = 100
+)
+
+// AuditRecord describes a level change made through SetLevelBy
+type AuditRecord struct {
+	Time     time.Time
+	Module   string
+	OldLevel logrus.Level
+	NewLevel logrus.Level
+	Actor    string
+	Reason   string
+}
+
+// auditLog holds the most recent audit records of a root logger
+type auditLog struct {
+	mutex   sync.Mutex
+	size    *int
+	records []AuditRecord
+}
+
+func (lm *loggerModule) SetLevelBy(level logrus.Level, actor, reason string) {
+	var events []Event
+	oldLevel := lm.setLevel(level, Event{Source: EventSourceSetLevel, Actor: actor, Reason: reason}, &events)
+
+	if root, ok := lm.root.(interface{ audit(record AuditRecord) }); ok {
+		root.audit(AuditRecord{
+			Time:     lm.now(),
+			Module:   lm.name,
+			OldLevel: oldLevel,
+			NewLevel: level,
+			Actor:    actor,
+			Reason:   reason,
+		})
+	}
+	lm.publish(events)
+}
+
+// auditModuleName returns the name of the audit module for the given separator
+func auditModuleName(separator string) string {
+	return strings.Join(strings.Split(AuditModuleName, DefaultSeparator), separator)
+}
+
+// audit adds the record to the history and logs it through the audit module.
+// The entry is written regardless of the audit module's level and of the level of the underlying logrus.Logger,
+// so that level changes cannot hide themselves.
+func (lr *loggerRoot) audit(record AuditRecord) {
+	lr.auditLog.mutex.Lock()
+	size := DefaultAuditHistorySize
+	if lr.auditLog.size != nil {
+		size = *lr.auditLog.size
+	}
+	lr.auditLog.records = append(lr.auditLog.records, record)
+	if len(lr.auditLog.records) > size {
+		lr.auditLog.records = append([]AuditRecord(nil), lr.auditLog.records[len(lr.auditLog.records)-size:]...)
+	}
+	lr.auditLog.mutex.Unlock()
+
+	auditModule := lr.GetOrCreateChild(auditModuleName(lr.GetSeparator()), logrus.InfoLevel)
+	if auditModule == nil {
+		return
+	}
+	fields := logrus.Fields{
+		"target_module": record.Module,
+		"old_level":     record.OldLevel.String(),
+		"new_level":     record.NewLevel.String(),
+		"actor":         record.Actor,
+		"reason":        record.Reason,
+	}
+	redaction := newRedaction(auditModule, settingsOf(auditModule))
+	for fieldName, fieldValue := range fields {
+		fields[fieldName] = redaction.redact(fieldName, fieldValue)
+	}
+	addModuleFields(fields, lr, auditModule)
+
+	lb := &loggerBase{moduleLogger: auditModule}
+	lb.emitUnfiltered(&logrus.Entry{
+		Logger:  lr.GetLogger(),
+		Data:    fields,
+		Time:    lr.GetClock().Now(),
+		Level:   logrus.InfoLevel,
+		Message: "Level changed",
+	}, statsOf(auditModule))
+}
+
+func (lr *loggerRoot) GetAuditRecords(moduleName string) []AuditRecord {
+	separator := lr.GetSeparator()
+
+	lr.auditLog.mutex.Lock()
+	defer lr.auditLog.mutex.Unlock()
+
+	var records []AuditRecord
+	for _, record := range lr.auditLog.records {
+		if moduleName == "" || record.Module == "" || record.Module == moduleName ||
+			strings.HasPrefix(moduleName, record.Module+separator) {
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+func (lr *loggerRoot) SetAuditHistorySize(size int) error {
+	if size < 0 {
+		return ErrInvalidAuditHistorySize
+	}
+
+	lr.auditLog.mutex.Lock()
+	defer lr.auditLog.mutex.Unlock()
+
+	lr.auditLog.size = &size
+	if len(lr.auditLog.records) > size {
+		lr.auditLog.records = append([]AuditRecord(nil), lr.auditLog.records[len(lr.auditLog.records)-size:]...)
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package modular
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerModule_SetLevelBy(t *testing.T) {
+	buffer := bytes.NewBufferString("")
+	logger := logrus.New()
+	logger.Out = buffer
+	logger.Formatter = &logrus.JSONFormatter{}
+	clock := &fixedClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+	rl, err := New(logger, WithLevel(logrus.InfoLevel), WithClock(clock))
+	require.NoError(t, err)
+
+	pii := rl.GetOrCreateChild("users.pii", logrus.InfoLevel)
+
+	var events []Event
+	rl.Subscribe(func(event Event) {
+		if event.Type == EventLevelChanged {
+			events = append(events, event)
+		}
+	})
+
+	users, err := rl.GetChild("users")
+	require.NoError(t, err)
+	users.SetLevelBy(logrus.DebugLevel, "alice", "incident 42")
+	require.EqualValues(t, logrus.DebugLevel, pii.GetLevel())
+
+	// Events carry actor and reason
+	require.Len(t, events, 2)
+	require.EqualValues(t, "alice", events[1].Actor)
+	require.EqualValues(t, "incident 42", events[1].Reason)
+	require.EqualValues(t, EventSourceParent, events[1].Source)
+
+	record := AuditRecord{
+		Time:     clock.now,
+		Module:   "users",
+		OldLevel: logrus.InfoLevel,
+		NewLevel: logrus.DebugLevel,
+		Actor:    "alice",
+		Reason:   "incident 42",
+	}
+	require.EqualValues(t, []AuditRecord{record}, rl.GetAuditRecords("users.pii"))
+	require.EqualValues(t, []AuditRecord{record}, rl.GetAuditRecords(""))
+	require.Empty(t, rl.GetAuditRecords("usersx"))
+	require.Empty(t, rl.GetAuditRecords("http"))
+
+	// Changes are logged through the audit module
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(buffer.Bytes(), &entry))
+	require.EqualValues(t, AuditModuleName, entry["module"])
+	require.EqualValues(t, "Level changed", entry["msg"])
+	require.EqualValues(t, "users", entry["target_module"])
+	require.EqualValues(t, "info", entry["old_level"])
+	require.EqualValues(t, "debug", entry["new_level"])
+	require.EqualValues(t, "alice", entry["actor"])
+	require.EqualValues(t, "incident 42", entry["reason"])
+
+	// Changes to the root affect all modules
+	rl.SetLevelBy(logrus.InfoLevel, "bob", "")
+	records := rl.GetAuditRecords("http")
+	require.Len(t, records, 1)
+	require.EqualValues(t, "bob", records[0].Actor)
+	require.Len(t, rl.GetAuditRecords("users.pii"), 2)
+
+	// SetLevel is not audited
+	pii.SetLevel(logrus.TraceLevel)
+	require.Len(t, rl.GetAuditRecords(""), 2)
+}
+
+func TestLoggerModule_SetLevelBy_AboveInfo(t *testing.T) {
+	buffer := bytes.NewBufferString("")
+	logger := logrus.New()
+	logger.Out = buffer
+	logger.Formatter = &logrus.JSONFormatter{}
+	rl, err := New(logger, WithLevel(logrus.InfoLevel))
+	require.NoError(t, err)
+
+	// Changes are logged even if the level propagated to the audit module disables info entries
+	rl.SetLevelBy(logrus.WarnLevel, "alice", "")
+	rl.SetLevelBy(logrus.ErrorLevel, "mallory", "hide")
+	auditModule, err := rl.GetChild(AuditModuleName)
+	require.NoError(t, err)
+	require.EqualValues(t, logrus.ErrorLevel, auditModule.GetLevel())
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	require.Len(t, lines, 2)
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &entry))
+	require.EqualValues(t, "info", entry["level"])
+	require.EqualValues(t, "mallory", entry["actor"])
+	require.EqualValues(t, "error", entry["new_level"])
+	require.EqualValues(t, 2, auditModule.Stats().Emitted[logrus.InfoLevel])
+}
+
+func TestLoggerModule_SetLevelBy_LoggerLevel(t *testing.T) {
+	buffer := bytes.NewBufferString("")
+	logger := logrus.New()
+	logger.Out = buffer
+	logger.Formatter = &logrus.JSONFormatter{}
+	rl := NewRootLogger(logger)
+	logger.SetLevel(logrus.ErrorLevel)
+
+	// Changes are logged even if the logrus.Logger's level disables info entries
+	rl.SetLevelBy(logrus.DebugLevel, "alice", "")
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(buffer.Bytes(), &entry))
+	require.EqualValues(t, "info", entry["level"])
+	require.EqualValues(t, "alice", entry["actor"])
+	require.EqualValues(t, logrus.ErrorLevel, logger.GetLevel())
+}
+
+func TestLoggerModule_SetLevelBy_Separator(t *testing.T) {
+	buffer := bytes.NewBufferString("")
+	logger := logrus.New()
+	logger.Out = buffer
+	logger.Formatter = &logrus.JSONFormatter{}
+	rl, err := New(logger, WithSeparator("/"))
+	require.NoError(t, err)
+
+	// The audit module is named using the root's separator
+	rl.SetLevelBy(logrus.DebugLevel, "alice", "")
+	auditModule, err := rl.GetChild("logging/audit")
+	require.NoError(t, err)
+	require.EqualValues(t, []string{"logging", "audit"}, auditModule.GetModulePath())
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(buffer.Bytes(), &entry))
+	require.EqualValues(t, "logging/audit", entry["module"])
+}
+
+func TestLoggerRoot_SetAuditHistorySize(t *testing.T) {
+	logger := logrus.New()
+	logger.Out = bytes.NewBufferString("")
+	rl := NewRootLogger(logger)
+	require.EqualValues(t, ErrInvalidAuditHistorySize, rl.SetAuditHistorySize(-1))
+
+	for i := 0; i < DefaultAuditHistorySize+10; i++ {
+		rl.SetLevelBy(logrus.InfoLevel, strings.Repeat("a", i), "")
+	}
+	records := rl.GetAuditRecords("")
+	require.Len(t, records, DefaultAuditHistorySize)
+	require.EqualValues(t, strings.Repeat("a", 10), records[0].Actor)
+
+	// Shrinking drops the oldest records
+	require.NoError(t, rl.SetAuditHistorySize(2))
+	records = rl.GetAuditRecords("")
+	require.Len(t, records, 2)
+	require.EqualValues(t, strings.Repeat("a", DefaultAuditHistorySize+8), records[0].Actor)
+
+	rl.SetLevelBy(logrus.InfoLevel, "last", "")
+	records = rl.GetAuditRecords("")
+	require.Len(t, records, 2)
+	require.EqualValues(t, "last", records[1].Actor)
+
+	require.NoError(t, rl.SetAuditHistorySize(0))
+	rl.SetLevelBy(logrus.InfoLevel, "none", "")
+	require.Empty(t, rl.GetAuditRecords(""))
+}
@@ -0,0 +1,17 @@
+package modular
+
+import "time"
+
+// Clock provides the current time, i.e. for entry timestamps
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// SystemClock is the Clock used by default, based on time.Now
+var SystemClock Clock = systemClock{}
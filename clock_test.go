@@ -0,0 +1,15 @@
+package modular
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSystemClock(t *testing.T) {
+	before := time.Now()
+	now := SystemClock.Now()
+	require.False(t, now.Before(before))
+	require.False(t, now.After(time.Now()))
+}
@@ -0,0 +1,201 @@
+package modular
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultRequestLevelHeader defines the default header carrying per-request levels
+const DefaultRequestLevelHeader = "X-Log-Level"
+
+type contextLevelKey struct{}
+
+// contextLevel is a level scoped to a module prefix, chained to levels set on parent contexts
+type contextLevel struct {
+	prefix string
+	level  logrus.Level
+	parent *contextLevel
+}
+
+// ContextWithLevel returns a context which raises the level of all modules with the given prefix,
+// or of all modules if the prefix is empty, for entries logged through loggers carrying the context,
+// see Logger.WithContext. The level only applies if it is more verbose than the module's level.
+// Unless the root logger was created by New, the level of the underlying logrus.Logger still acts as an upper bound.
+func ContextWithLevel(ctx context.Context, prefix string, level logrus.Level) context.Context {
+	parent, _ := ctx.Value(contextLevelKey{}).(*contextLevel)
+	return context.WithValue(ctx, contextLevelKey{}, &contextLevel{
+		prefix: prefix,
+		level:  level,
+		parent: parent,
+	})
+}
+
+// LevelFromContext returns the most verbose level set by ContextWithLevel for the module with the given full name
+func LevelFromContext(ctx context.Context, moduleName, separator string) (logrus.Level, bool) {
+	if ctx == nil {
+		return 0, false
+	}
+
+	found := false
+	var level logrus.Level
+	cl, _ := ctx.Value(contextLevelKey{}).(*contextLevel)
+	for ; cl != nil; cl = cl.parent {
+		if cl.prefix != "" && moduleName != cl.prefix && !strings.HasPrefix(moduleName, cl.prefix+separator) {
+			continue
+		}
+		if !found || cl.level > level {
+			level = cl.level
+			found = true
+		}
+	}
+	return level, found
+}
+
+// RequestEscalator raises levels for single requests, based on a header such as "X-Log-Level: debug"
+// or "X-Log-Level: db=debug,http=trace", the latter being scoped to module prefixes.
+// The number of requests holding raised levels at the same time is limited, as are the levels
+// and modules requests may raise, see WithMaxEscalationLevel and WithEscalationPrefixes.
+type RequestEscalator struct {
+	header   string
+	limit    int64
+	maxLevel logrus.Level
+	prefixes []string
+	active   int64
+}
+
+// EscalatorOption configures a RequestEscalator
+type EscalatorOption func(re *RequestEscalator)
+
+// WithMaxEscalationLevel limits the levels requests may raise modules to, more verbose levels are lowered
+// to the given level. By default, requests may raise modules up to TraceLevel.
+func WithMaxEscalationLevel(level logrus.Level) EscalatorOption {
+	return func(re *RequestEscalator) {
+		re.maxLevel = level
+	}
+}
+
+// WithEscalationPrefixes limits the modules requests may raise the level of to the given module prefixes
+// and their descendants, separated by DefaultSeparator. Values naming other modules, or no module at all,
+// are rejected. By default, requests may raise the level of all modules.
+func WithEscalationPrefixes(prefixes ...string) EscalatorOption {
+	return func(re *RequestEscalator) {
+		re.prefixes = append([]string(nil), prefixes...)
+	}
+}
+
+// NewRequestEscalator creates a new RequestEscalator reading levels from the given header,
+// allowing at most limit requests to hold raised levels at the same time
+func NewRequestEscalator(header string, limit int, opts ...EscalatorOption) *RequestEscalator {
+	if header == "" {
+		header = DefaultRequestLevelHeader
+	}
+	re := &RequestEscalator{
+		header:   header,
+		limit:    int64(limit),
+		maxLevel: logrus.TraceLevel,
+	}
+	for _, opt := range opts {
+		opt(re)
+	}
+	return re
+}
+
+// allows reports whether requests may raise the level of the given module prefix
+func (re *RequestEscalator) allows(moduleName string) bool {
+	if re.prefixes == nil {
+		return true
+	}
+	for _, prefix := range re.prefixes {
+		if moduleName == prefix || (moduleName != "" && prefix != "" && strings.HasPrefix(moduleName, prefix+DefaultSeparator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Active returns the number of requests currently holding raised levels
+func (re *RequestEscalator) Active() int {
+	return int(atomic.LoadInt64(&re.active))
+}
+
+// Escalate returns a context carrying the levels parsed from the header value and a function which must be
+// called once the request has finished. If the value is empty or invalid, contains module patterns
+// such as "*.sql=debug" or modules which are not allowed, or the limit has been reached, the context
+// is returned unchanged and ok is false. Levels more verbose than the maximum level are lowered to it.
+func (re *RequestEscalator) Escalate(ctx context.Context, value string) (escalated context.Context, release func(), ok bool) {
+	release = func() {}
+	if value == "" {
+		return ctx, release, false
+	}
+
+	spec, err := ParseLevelSpec(value)
+	if err != nil || len(spec) == 0 {
+		return ctx, release, false
+	}
+	for _, entry := range spec {
+		if isLevelPattern(entry.Module) || !re.allows(entry.Module) {
+			return ctx, release, false
+		}
+	}
+
+	if atomic.AddInt64(&re.active, 1) > re.limit {
+		atomic.AddInt64(&re.active, -1)
+		return ctx, release, false
+	}
+
+	for _, entry := range spec {
+		level := entry.Level
+		if level > re.maxLevel {
+			level = re.maxLevel
+		}
+		ctx = ContextWithLevel(ctx, entry.Module, level)
+	}
+
+	released := int32(0)
+	release = func() {
+		if atomic.CompareAndSwapInt32(&released, 0, 1) {
+			atomic.AddInt64(&re.active, -1)
+		}
+	}
+	return ctx, release, true
+}
+
+// Middleware returns an http.Handler which raises levels for requests carrying the header
+func (re *RequestEscalator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, release, ok := re.Escalate(r.Context(), r.Header.Get(re.header))
+		defer release()
+		if ok {
+			r = r.WithContext(ctx)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// UnaryHandler mirrors grpc.UnaryHandler, to which it can be converted both ways
+type UnaryHandler func(ctx context.Context, req interface{}) (interface{}, error)
+
+// WrapUnaryHandler returns a UnaryHandler which raises levels for requests carrying the header and calls handler.
+// As this package does not depend on gRPC, it is meant to be called from a grpc.UnaryServerInterceptor:
+//
+//	func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+//		return escalator.WrapUnaryHandler(incomingMetadata, modular.UnaryHandler(handler))(ctx, req)
+//	}
+//
+// Incoming metadata is provided by md, i.e. by wrapping metadata.FromIncomingContext, and looked up
+// by the lower case header name, as gRPC normalizes metadata keys.
+func (re *RequestEscalator) WrapUnaryHandler(md func(ctx context.Context) (map[string][]string, bool), handler UnaryHandler) UnaryHandler {
+	key := strings.ToLower(re.header)
+	return func(ctx context.Context, req interface{}) (interface{}, error) {
+		if metadata, ok := md(ctx); ok && len(metadata[key]) > 0 {
+			escalated, release, _ := re.Escalate(ctx, metadata[key][0])
+			defer release()
+			ctx = escalated
+		}
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,210 @@
+package modular
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLevelFromContext(t *testing.T) {
+	_, ok := LevelFromContext(context.Background(), "db", ".")
+	require.False(t, ok)
+
+	ctx := ContextWithLevel(context.Background(), "db", logrus.DebugLevel)
+	ctx = ContextWithLevel(ctx, "db.sql", logrus.TraceLevel)
+	ctx = ContextWithLevel(ctx, "db", logrus.InfoLevel)
+
+	level, ok := LevelFromContext(ctx, "db", ".")
+	require.True(t, ok)
+	require.EqualValues(t, logrus.DebugLevel, level)
+
+	level, ok = LevelFromContext(ctx, "db.sql.conn", ".")
+	require.True(t, ok)
+	require.EqualValues(t, logrus.TraceLevel, level)
+
+	_, ok = LevelFromContext(ctx, "dbx", ".")
+	require.False(t, ok)
+
+	ctx = ContextWithLevel(context.Background(), "", logrus.DebugLevel)
+	level, ok = LevelFromContext(ctx, "http", ".")
+	require.True(t, ok)
+	require.EqualValues(t, logrus.DebugLevel, level)
+}
+
+func TestLoggerBase_WithContext(t *testing.T) {
+	buffer := bytes.NewBufferString("")
+	logger := logrus.New()
+	logger.Out = buffer
+	logger.Level = logrus.DebugLevel
+	rl := NewRootLogger(logger)
+	db := rl.GetOrCreateChild("db", logrus.InfoLevel)
+	http := rl.GetOrCreateChild("http", logrus.InfoLevel)
+
+	ctx := ContextWithLevel(context.Background(), "db", logrus.DebugLevel)
+
+	// Without the context, Debug is suppressed
+	db.Debug("test")
+	require.Empty(t, buffer.Bytes())
+
+	// With the context, Debug is logged for "db" only
+	db.WithContext(ctx).WithField("key", "value").Debug("test")
+	require.NotEmpty(t, buffer.Bytes())
+
+	buffer.Reset()
+	http.WithContext(ctx).Debug("test")
+	require.Empty(t, buffer.Bytes())
+
+	// Module levels are not changed
+	require.EqualValues(t, logrus.InfoLevel, db.GetLevel())
+
+	// Contexts do not lower levels
+	ctx = ContextWithLevel(context.Background(), "", logrus.ErrorLevel)
+	db.WithContext(ctx).Info("test")
+	require.NotEmpty(t, buffer.Bytes())
+
+	// Context is passed on to entries
+	lb := db.WithContext(ctx).(*loggerBase)
+	require.EqualValues(t, ctx, lb.newEntry(logrus.InfoLevel).Context)
+}
+
+func TestLoggerBase_WithContext_New(t *testing.T) {
+	buffer := bytes.NewBufferString("")
+	logger := logrus.New()
+	logger.Out = buffer
+	logger.Level = logrus.InfoLevel
+	rl, err := New(logger)
+	require.NoError(t, err)
+	db := rl.GetOrCreateChild("db", logrus.InfoLevel)
+
+	// The logger's level does not bound levels raised by contexts of root loggers created by New
+	ctx := ContextWithLevel(context.Background(), "db", logrus.DebugLevel)
+	db.WithContext(ctx).Debug("test")
+	require.NotEmpty(t, buffer.Bytes())
+	require.EqualValues(t, logrus.InfoLevel, logger.GetLevel())
+}
+
+func TestRequestEscalator_Escalate(t *testing.T) {
+	re := NewRequestEscalator("", 1)
+
+	ctx, release, ok := re.Escalate(context.Background(), "warn,db=debug")
+	require.True(t, ok)
+	require.EqualValues(t, 1, re.Active())
+	level, found := LevelFromContext(ctx, "db", ".")
+	require.True(t, found)
+	require.EqualValues(t, logrus.DebugLevel, level)
+	level, found = LevelFromContext(ctx, "http", ".")
+	require.True(t, found)
+	require.EqualValues(t, logrus.WarnLevel, level)
+
+	// Limit reached
+	ctx2, release2, ok := re.Escalate(context.Background(), "debug")
+	require.False(t, ok)
+	_, found = LevelFromContext(ctx2, "db", ".")
+	require.False(t, found)
+	release2()
+	require.EqualValues(t, 1, re.Active())
+
+	// Releasing twice only frees one slot
+	release()
+	release()
+	require.EqualValues(t, 0, re.Active())
+
+	// Empty, invalid and pattern values hold no slot
+	for _, value := range []string{"", "verbose", ",", "*.sql=debug", "db=info,re:^http=debug"} {
+		_, _, ok = re.Escalate(context.Background(), value)
+		require.False(t, ok)
+		require.EqualValues(t, 0, re.Active())
+	}
+}
+
+func TestRequestEscalator_Middleware(t *testing.T) {
+	re := NewRequestEscalator("X-Debug", 10)
+
+	var level logrus.Level
+	var found bool
+	handler := re.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		level, found = LevelFromContext(r.Context(), "db", ".")
+		require.EqualValues(t, 1, re.Active())
+	}))
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("X-Debug", "db=trace")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	require.True(t, found)
+	require.EqualValues(t, logrus.TraceLevel, level)
+	require.EqualValues(t, 0, re.Active())
+
+	handler = re.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, found = LevelFromContext(r.Context(), "db", ".")
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	require.False(t, found)
+}
+
+func TestRequestEscalator_Escalate_Options(t *testing.T) {
+	re := NewRequestEscalator("", 10, WithMaxEscalationLevel(logrus.DebugLevel), WithEscalationPrefixes("db", "http.client"))
+
+	// Levels are capped
+	ctx, release, ok := re.Escalate(context.Background(), "db=trace,http.client=info")
+	require.True(t, ok)
+	level, found := LevelFromContext(ctx, "db.sql", ".")
+	require.True(t, found)
+	require.EqualValues(t, logrus.DebugLevel, level)
+	level, found = LevelFromContext(ctx, "http.client", ".")
+	require.True(t, found)
+	require.EqualValues(t, logrus.InfoLevel, level)
+	release()
+
+	// Modules outside of the allowed prefixes are rejected
+	for _, value := range []string{"debug", "db=debug,http=debug", "dbx=debug", "http.client.pool=debug,cache=debug"} {
+		_, _, ok = re.Escalate(context.Background(), value)
+		require.False(t, ok, value)
+		require.EqualValues(t, 0, re.Active())
+	}
+	_, release, ok = re.Escalate(context.Background(), "db.sql=debug,http.client.pool=debug")
+	require.True(t, ok)
+	release()
+
+	// An empty prefix allows the root module
+	re = NewRequestEscalator("", 10, WithEscalationPrefixes(""))
+	_, release, ok = re.Escalate(context.Background(), "debug")
+	require.True(t, ok)
+	release()
+	_, _, ok = re.Escalate(context.Background(), "db=debug")
+	require.False(t, ok)
+}
+
+func TestRequestEscalator_WrapUnaryHandler(t *testing.T) {
+	re := NewRequestEscalator(DefaultRequestLevelHeader, 10)
+	type mdKey struct{}
+	md := func(ctx context.Context) (map[string][]string, bool) {
+		md, ok := ctx.Value(mdKey{}).(map[string][]string)
+		return md, ok
+	}
+
+	ctx := context.WithValue(context.Background(), mdKey{}, map[string][]string{
+		"x-log-level": {"debug"},
+	})
+	resp, err := re.WrapUnaryHandler(md, func(ctx context.Context, req interface{}) (interface{}, error) {
+		level, found := LevelFromContext(ctx, "db", ".")
+		require.True(t, found)
+		require.EqualValues(t, logrus.DebugLevel, level)
+		require.EqualValues(t, 1, re.Active())
+		return "response", nil
+	})(ctx, "request")
+	require.NoError(t, err)
+	require.EqualValues(t, "response", resp)
+	require.EqualValues(t, 0, re.Active())
+
+	_, err = re.WrapUnaryHandler(md, func(ctx context.Context, req interface{}) (interface{}, error) {
+		_, found := LevelFromContext(ctx, "db", ".")
+		require.False(t, found)
+		return nil, nil
+	})(context.Background(), "request")
+	require.NoError(t, err)
+}
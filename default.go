@@ -0,0 +1,37 @@
+package modular
+
+import "sync"
+
+var (
+	defaultRootMutex sync.Mutex
+	defaultRoot      RootLogger
+)
+
+// Default returns the package-level root logger.
+// Unless replaced by SetDefault, it is created on first use by NewStandalone without options.
+func Default() RootLogger {
+	defaultRootMutex.Lock()
+	defer defaultRootMutex.Unlock()
+
+	if defaultRoot == nil {
+		// Cannot fail without options
+		defaultRoot, _ = NewStandalone()
+	}
+	return defaultRoot
+}
+
+// SetDefault replaces the package-level root logger.
+// Passing nil resets it, so Default creates a new one on next use.
+func SetDefault(root RootLogger) {
+	defaultRootMutex.Lock()
+	defer defaultRootMutex.Unlock()
+
+	defaultRoot = root
+}
+
+// Module returns the module logger with the given name from the package-level root logger,
+// creating it with the root's level if it is missing. Returns nil if the module name is invalid.
+func Module(moduleName string) ModuleLogger {
+	root := Default()
+	return root.GetOrCreateChild(moduleName, root.GetLevel())
+}
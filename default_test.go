@@ -0,0 +1,57 @@
+package modular
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefault(t *testing.T) {
+	defer SetDefault(nil)
+	SetDefault(nil)
+
+	// Lazily initialised once, also under concurrent use
+	roots := make([]RootLogger, 10)
+	wg := sync.WaitGroup{}
+	for i := range roots {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			roots[i] = Default()
+		}(i)
+	}
+	wg.Wait()
+
+	require.NotNil(t, roots[0])
+	for _, root := range roots {
+		require.True(t, roots[0] == root)
+	}
+
+	// Replaceable
+	rl := NewRootLogger(logrus.New())
+	SetDefault(rl)
+	require.True(t, rl == Default())
+
+	// Reset
+	SetDefault(nil)
+	require.NotNil(t, Default())
+	require.False(t, rl == Default())
+}
+
+func TestModule(t *testing.T) {
+	defer SetDefault(nil)
+	rl := NewRootLogger(logrus.New())
+	rl.SetLevel(logrus.WarnLevel)
+	SetDefault(rl)
+
+	module := Module("db.query")
+	require.NotNil(t, module)
+	require.EqualValues(t, "db.query", module.GetModuleName())
+	require.EqualValues(t, logrus.WarnLevel, module.GetLevel())
+	require.EqualValues(t, rl, module.GetRoot())
+	require.EqualValues(t, module, Module("db.query"))
+
+	require.Nil(t, Module("db..query"))
+}
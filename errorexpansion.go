@@ -0,0 +1,126 @@
+package modular
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// ErrorChainField defines the field holding the messages of an expanded error chain
+	ErrorChainField = "error.chain"
+	// ErrorStackField defines the field holding the stack trace of an expanded error
+	ErrorStackField = "error.stack"
+	// ErrorFieldPrefix defines the prefix of fields extracted from errors implementing ErrorFielder
+	ErrorFieldPrefix = "error."
+)
+
+// maxErrorChainLength limits the number of errors visited when expanding an error
+const maxErrorChainLength = 32
+
+// ErrorFielder is implemented by errors which carry fields
+type ErrorFielder interface {
+	Fields() logrus.Fields
+}
+
+// ErrorExpansion defines how errors passed through WithError are expanded into additional fields.
+// Errors are unwrapped through Unwrap() error and Unwrap() []error, as returned by errors.Join.
+type ErrorExpansion struct {
+	// Chain adds the messages of all errors in the chain to ErrorChainField, starting with the error itself
+	Chain bool
+	// Fields adds the fields of all errors in the chain implementing ErrorFielder, prefixed by ErrorFieldPrefix.
+	// Fields of outer errors take precedence.
+	Fields bool
+	// Stack adds the stack trace of the innermost error carrying one to ErrorStackField.
+	// Errors carry a stack trace if they implement StackTrace() with a single result, as errors of
+	// github.com/pkg/errors do, or Stack() []byte.
+	Stack bool
+}
+
+func (lm *loggerModule) SetErrorExpansion(expansion ErrorExpansion) {
+	lm.settingsMutex.Lock()
+	defer lm.settingsMutex.Unlock()
+	lm.errorExpansion = &expansion
+	invalidateSettings()
+}
+
+func (lm *loggerModule) GetErrorExpansion() ErrorExpansion {
+	return lm.resolveSettings().errorExpansion
+}
+
+// expandError returns the fields for the given error according to the expansion
+func expandError(err error, expansion ErrorExpansion) logrus.Fields {
+	if !expansion.Chain && !expansion.Fields && !expansion.Stack {
+		return nil
+	}
+
+	chain := unwrapErrorChain(err)
+	fields := make(logrus.Fields)
+
+	if expansion.Chain {
+		messages := make([]string, len(chain))
+		for i, chainErr := range chain {
+			messages[i] = chainErr.Error()
+		}
+		fields[ErrorChainField] = messages
+	}
+
+	if expansion.Fields {
+		for i := len(chain) - 1; i >= 0; i-- {
+			if fielder, ok := chain[i].(ErrorFielder); ok {
+				for key, value := range fielder.Fields() {
+					fields[ErrorFieldPrefix+key] = value
+				}
+			}
+		}
+	}
+
+	if expansion.Stack {
+		for i := len(chain) - 1; i >= 0; i-- {
+			if stack, ok := errorStack(chain[i]); ok {
+				fields[ErrorStackField] = stack
+				break
+			}
+		}
+	}
+
+	return fields
+}
+
+// unwrapErrorChain returns the error and all errors it wraps, depth-first
+func unwrapErrorChain(err error) []error {
+	var chain []error
+	var visit func(err error)
+	visit = func(err error) {
+		if err == nil || len(chain) >= maxErrorChainLength {
+			return
+		}
+		chain = append(chain, err)
+
+		switch wrapper := err.(type) {
+		case interface{ Unwrap() []error }:
+			for _, wrapped := range wrapper.Unwrap() {
+				visit(wrapped)
+			}
+		default:
+			visit(errors.Unwrap(err))
+		}
+	}
+	visit(err)
+	return chain
+}
+
+// errorStack returns the formatted stack trace carried by the error, if any
+func errorStack(err error) (string, bool) {
+	if stacker, ok := err.(interface{ Stack() []byte }); ok {
+		return string(stacker.Stack()), true
+	}
+
+	method := reflect.ValueOf(err).MethodByName("StackTrace")
+	if !method.IsValid() || method.Type().NumIn() != 0 || method.Type().NumOut() != 1 {
+		return "", false
+	}
+	return fmt.Sprintf("%+v", method.Call(nil)[0].Interface()), true
+}
@@ -0,0 +1,194 @@
+package modular
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+type fieldsError struct {
+	msg    string
+	fields logrus.Fields
+	err    error
+}
+
+func (fe *fieldsError) Error() string {
+	return fe.msg
+}
+
+func (fe *fieldsError) Fields() logrus.Fields {
+	return fe.fields
+}
+
+func (fe *fieldsError) Unwrap() error {
+	return fe.err
+}
+
+type stackTrace []string
+
+type stackTraceError struct {
+	msg   string
+	stack stackTrace
+}
+
+func (ste *stackTraceError) Error() string {
+	return ste.msg
+}
+
+func (ste *stackTraceError) StackTrace() stackTrace {
+	return ste.stack
+}
+
+type stackError struct {
+	error
+}
+
+func (se stackError) Unwrap() error {
+	return se.error
+}
+
+func (se stackError) Stack() []byte {
+	return []byte("goroutine 1 [running]")
+}
+
+func TestUnwrapErrorChain(t *testing.T) {
+	require.Empty(t, unwrapErrorChain(nil))
+
+	inner := errors.New("inner")
+	outer := fmt.Errorf("outer: %w", inner)
+	require.EqualValues(t, []error{outer, inner}, unwrapErrorChain(outer))
+
+	other := errors.New("other")
+	joined := errors.Join(outer, other)
+	require.EqualValues(t, []error{joined, outer, inner, other}, unwrapErrorChain(joined))
+
+	// Chains are limited in length
+	err := inner
+	for i := 0; i < maxErrorChainLength*2; i++ {
+		err = fmt.Errorf("wrapped: %w", err)
+	}
+	require.Len(t, unwrapErrorChain(err), maxErrorChainLength)
+}
+
+func TestErrorStack(t *testing.T) {
+	_, ok := errorStack(errors.New("plain"))
+	require.False(t, ok)
+
+	stack, ok := errorStack(&stackTraceError{msg: "test", stack: stackTrace{"main.go:1"}})
+	require.True(t, ok)
+	require.EqualValues(t, "[main.go:1]", stack)
+
+	stack, ok = errorStack(stackError{errors.New("test")})
+	require.True(t, ok)
+	require.EqualValues(t, "goroutine 1 [running]", stack)
+}
+
+func TestExpandError(t *testing.T) {
+	inner := &stackTraceError{msg: "connection refused", stack: stackTrace{"db.go:10"}}
+	middle := &fieldsError{msg: "query failed", fields: logrus.Fields{"table": "users", "id": 1}, err: inner}
+	outer := &fieldsError{msg: "load user: query failed", fields: logrus.Fields{"id": 2}, err: middle}
+	err := fmt.Errorf("request: %w", stackError{outer})
+
+	require.Nil(t, expandError(err, ErrorExpansion{}))
+
+	require.EqualValues(t, logrus.Fields{
+		ErrorChainField: []string{"request: load user: query failed", "load user: query failed", "load user: query failed", "query failed", "connection refused"},
+	}, expandError(err, ErrorExpansion{Chain: true}))
+
+	require.EqualValues(t, logrus.Fields{
+		"error.table": "users",
+		"error.id":    2,
+	}, expandError(err, ErrorExpansion{Fields: true}))
+
+	// The innermost stack trace is used
+	require.EqualValues(t, logrus.Fields{
+		ErrorStackField: "[db.go:10]",
+	}, expandError(err, ErrorExpansion{Stack: true}))
+}
+
+func TestLoggerModule_SetErrorExpansion(t *testing.T) {
+	rl := NewRootLogger(logrus.New())
+	child := rl.GetOrCreateChild("child", logrus.InfoLevel)
+	require.EqualValues(t, ErrorExpansion{}, child.GetErrorExpansion())
+
+	rl.SetErrorExpansion(ErrorExpansion{Chain: true})
+	require.EqualValues(t, ErrorExpansion{Chain: true}, child.GetErrorExpansion())
+
+	child.SetErrorExpansion(ErrorExpansion{Fields: true})
+	require.EqualValues(t, ErrorExpansion{Fields: true}, child.GetErrorExpansion())
+	require.EqualValues(t, ErrorExpansion{Chain: true}, rl.GetErrorExpansion())
+}
+
+func TestLoggerBase_ErrorExpansion(t *testing.T) {
+	buffer := bytes.NewBufferString("")
+	logger := logrus.New()
+	logger.Out = buffer
+	logger.Formatter = &logrus.JSONFormatter{}
+	rl := NewRootLogger(logger)
+
+	domain := rl.GetOrCreateChild("domain", logrus.InfoLevel)
+	domain.SetErrorExpansion(ErrorExpansion{Chain: true, Fields: true})
+	domain.SetRedactionRules(RedactKey("error.email"))
+	other := rl.GetOrCreateChild("other", logrus.InfoLevel)
+
+	err := errors.Join(
+		&fieldsError{msg: "invalid user", fields: logrus.Fields{"user": 42, "email": "alice@example.com"}},
+		errors.New("invalid order"),
+	)
+
+	domain.WithError(err).Error("failed")
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(buffer.Bytes(), &entry))
+	require.EqualValues(t, "invalid user\ninvalid order", entry[logrus.ErrorKey])
+	require.EqualValues(t, []interface{}{"invalid user\ninvalid order", "invalid user", "invalid order"}, entry[ErrorChainField])
+	require.EqualValues(t, 42, entry["error.user"])
+	// Expanded fields are redacted as well
+	require.EqualValues(t, RedactedValue, entry["error.email"])
+
+	buffer.Reset()
+	other.WithError(err).Error("failed")
+	entry = nil
+	require.NoError(t, json.Unmarshal(buffer.Bytes(), &entry))
+	require.NotContains(t, entry, ErrorChainField)
+	require.NotContains(t, entry, "error.user")
+}
+
+func TestLoggerBase_ErrorExpansion_Redaction(t *testing.T) {
+	buffer := bytes.NewBufferString("")
+	logger := logrus.New()
+	logger.Out = buffer
+	logger.Formatter = &logrus.JSONFormatter{}
+	rl := NewRootLogger(logger)
+	rl.SetErrorExpansion(ErrorExpansion{Chain: true, Fields: true, Stack: true})
+	rl.SetRedactionRules(RedactCardNumbers, RedactKey("password"))
+
+	err := fmt.Errorf("payment failed: %w", &fieldsError{
+		msg: "card 4111111111111111 declined",
+		fields: logrus.Fields{
+			"card":    "4111111111111111",
+			"request": map[string]string{"password": "hunter2", "user": "alice"},
+		},
+		err: stackError{errors.New("card 4111111111111111 expired")},
+	})
+
+	rl.WithError(err).Error("failed")
+	require.NotContains(t, buffer.String(), "4111111111111111")
+	require.NotContains(t, buffer.String(), "hunter2")
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(buffer.Bytes(), &entry))
+	require.EqualValues(t, []interface{}{
+		"payment failed: card " + RedactedValue + " declined",
+		"card " + RedactedValue + " declined",
+		"card " + RedactedValue + " expired",
+		"card " + RedactedValue + " expired",
+	}, entry[ErrorChainField])
+	require.EqualValues(t, RedactedValue, entry["error.card"])
+	require.EqualValues(t, map[string]interface{}{"password": RedactedValue, "user": "alice"}, entry["error.request"])
+	require.EqualValues(t, "goroutine 1 [running]", entry[ErrorStackField])
+}
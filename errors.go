@@ -1,10 +1,164 @@
 package modular
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+)
 
 var (
 	// ErrChildExists denotes that a child logger already exists
 	ErrChildExists = errors.New("Child logger exists")
 	// ErrChildNotFound denotes that a child logger was not found
 	ErrChildNotFound = errors.New("Child logger not found")
+	// ErrInvalidModuleName denotes that a module name is invalid
+	ErrInvalidModuleName = errors.New("Invalid module name")
+	// ErrEmptyModuleSegment denotes that a module name contains an empty segment, i.e. "a..b" or "a."
+	ErrEmptyModuleSegment = errors.New("Module name contains empty segment")
+	// ErrInvalidModuleCharacter denotes that a module name contains a character outside of the allowed charset
+	ErrInvalidModuleCharacter = errors.New("Module name contains invalid character")
+	// ErrModuleNameTooLong denotes that a module name exceeds MaxModuleNameLength
+	ErrModuleNameTooLong = errors.New("Module name too long")
+	// ErrModuleNameTooDeep denotes that a module name exceeds MaxModuleDepth segments
+	ErrModuleNameTooDeep = errors.New("Module name too deep")
+	// ErrInvalidSeparator denotes that an empty module separator was passed
+	ErrInvalidSeparator = errors.New("Invalid module separator")
+	// ErrSeparatorInUse denotes that the module separator cannot be changed as children exist already
+	ErrSeparatorInUse = errors.New("Module separator in use")
+	// ErrNilLogger denotes that a nil logrus.Logger was passed
+	ErrNilLogger = errors.New("Logger is nil")
+	// ErrInvalidAlias denotes that the target of an alias equals or lies within its source, i.e. "a" to "a.b"
+	ErrInvalidAlias = errors.New("Alias target within alias source")
+	// ErrInvalidLevel denotes that a level is not one of logrus.AllLevels
+	ErrInvalidLevel = errors.New("Invalid level")
+	// ErrInvalidFlightRecorderSize denotes that a negative flight recorder size was passed
+	ErrInvalidFlightRecorderSize = errors.New("Invalid flight recorder size")
+	// ErrInvalidDuration denotes that a duration is not positive
+	ErrInvalidDuration = errors.New("Invalid duration")
+	// ErrInvalidLevelSpec denotes that a level spec is invalid
+	ErrInvalidLevelSpec = errors.New("Invalid level spec")
+	// ErrInvalidLevelRule denotes that a level rule is not in the form "pattern=level"
+	ErrInvalidLevelRule = errors.New("Invalid level rule")
+	// ErrInvalidPattern denotes that the pattern of a level rule is invalid
+	ErrInvalidPattern = errors.New("Invalid pattern")
+	// ErrInvalidModuleField denotes that an empty module field was passed
+	ErrInvalidModuleField = errors.New("Invalid module field")
+	// ErrNilHook denotes that a nil hook was passed
+	ErrNilHook = errors.New("Hook is nil")
+	// ErrNilClock denotes that a nil clock was passed
+	ErrNilClock = errors.New("Clock is nil")
+	// ErrNilOutput denotes that a nil output was passed
+	ErrNilOutput = errors.New("Output is nil")
+	// ErrNilFormatter denotes that a nil formatter was passed
+	ErrNilFormatter = errors.New("Formatter is nil")
+	// ErrNilRoot denotes that a nil root logger was passed
+	ErrNilRoot = errors.New("Root logger is nil")
+	// ErrMountExists denotes that a root logger is mounted at the given prefix already
+	ErrMountExists = errors.New("Mount exists")
+	// ErrMountNotFound denotes that no root logger is mounted at the given prefix
+	ErrMountNotFound = errors.New("Mount not found")
+	// ErrInvalidAuditHistorySize denotes that a negative audit history size was passed
+	ErrInvalidAuditHistorySize = errors.New("Invalid audit history size")
+	// ErrInvalidExpvarName denotes that an empty expvar name was passed
+	ErrInvalidExpvarName = errors.New("Invalid expvar name")
+	// ErrExpvarExists denotes that an expvar with the given name is published already
+	ErrExpvarExists = errors.New("Expvar exists")
+	// ErrStandaloneOption denotes that an option configuring the logrus.Logger was passed to New,
+	// which leaves the passed logger untouched
+	ErrStandaloneOption = errors.New("Option only valid for standalone root loggers")
 )
+
+// ChildExistsError denotes that the child logger with the given module name already exists.
+// It matches ErrChildExists when used with errors.Is.
+type ChildExistsError struct {
+	Module string
+}
+
+func (e *ChildExistsError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrChildExists.Error(), e.Module)
+}
+
+// Is reports whether target is ErrChildExists
+func (e *ChildExistsError) Is(target error) bool {
+	return target == ErrChildExists
+}
+
+// ChildNotFoundError denotes that the child logger with the given module name was not found.
+// It matches ErrChildNotFound when used with errors.Is.
+type ChildNotFoundError struct {
+	Module string
+}
+
+func (e *ChildNotFoundError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrChildNotFound.Error(), e.Module)
+}
+
+// Is reports whether target is ErrChildNotFound
+func (e *ChildNotFoundError) Is(target error) bool {
+	return target == ErrChildNotFound
+}
+
+// ModuleNameError denotes that a module name is invalid.
+// It matches ErrInvalidModuleName when used with errors.Is and unwraps to the reason, i.e. ErrEmptyModuleSegment.
+type ModuleNameError struct {
+	// Name is the offending module name
+	Name string
+	// Position is the byte offset within Name at which the problem was detected
+	Position int
+	// Err is the reason why the module name is invalid
+	Err error
+}
+
+func (e *ModuleNameError) Error() string {
+	return fmt.Sprintf("%s %q at position %d: %s", ErrInvalidModuleName.Error(), e.Name, e.Position, e.Err.Error())
+}
+
+// Is reports whether target is ErrInvalidModuleName
+func (e *ModuleNameError) Is(target error) bool {
+	return target == ErrInvalidModuleName
+}
+
+// Unwrap returns the reason why the module name is invalid
+func (e *ModuleNameError) Unwrap() error {
+	return e.Err
+}
+
+// LevelSpecError denotes that an entry of a level spec is invalid.
+// It matches ErrInvalidLevelSpec when used with errors.Is and unwraps to the reason, i.e. ErrInvalidLevel.
+type LevelSpecError struct {
+	// Entry is the offending entry of the level spec
+	Entry string
+	// Err is the reason why the entry is invalid
+	Err error
+}
+
+func (e *LevelSpecError) Error() string {
+	return fmt.Sprintf("%s entry %q: %s", ErrInvalidLevelSpec.Error(), e.Entry, e.Err.Error())
+}
+
+// Is reports whether target is ErrInvalidLevelSpec
+func (e *LevelSpecError) Is(target error) bool {
+	return target == ErrInvalidLevelSpec
+}
+
+// Unwrap returns the reason why the entry is invalid
+func (e *LevelSpecError) Unwrap() error {
+	return e.Err
+}
+
+// FatalError is raised as panic for Fatal and Panic entries of modules using FatalActionPanic
+type FatalError struct {
+	// Module is the full name of the module which logged the entry
+	Module string
+	// Level is the level at which the entry was logged
+	Level logrus.Level
+	// Message is the message of the entry
+	Message string
+	// Fields holds the fields of the entry
+	Fields logrus.Fields
+}
+
+func (e *FatalError) Error() string {
+	return fmt.Sprintf("%s entry by module %q: %s", e.Level.String(), e.Module, e.Message)
+}
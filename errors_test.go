@@ -0,0 +1,36 @@
+package modular
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChildExistsError(t *testing.T) {
+	err := error(&ChildExistsError{Module: "db.query"})
+	require.EqualError(t, err, "Child logger exists: db.query")
+	require.True(t, errors.Is(err, ErrChildExists))
+	require.False(t, errors.Is(err, ErrChildNotFound))
+}
+
+func TestChildNotFoundError(t *testing.T) {
+	err := error(&ChildNotFoundError{Module: "db.query"})
+	require.EqualError(t, err, "Child logger not found: db.query")
+	require.True(t, errors.Is(err, ErrChildNotFound))
+	require.False(t, errors.Is(err, ErrChildExists))
+}
+
+func TestModuleNameError(t *testing.T) {
+	err := error(&ModuleNameError{Name: "a..b", Position: 2, Err: ErrEmptyModuleSegment})
+	require.EqualError(t, err, `Invalid module name "a..b" at position 2: Module name contains empty segment`)
+	require.True(t, errors.Is(err, ErrInvalidModuleName))
+	require.True(t, errors.Is(err, ErrEmptyModuleSegment))
+	require.False(t, errors.Is(err, ErrModuleNameTooLong))
+}
+
+func TestFatalError(t *testing.T) {
+	err := error(&FatalError{Module: "db", Level: logrus.FatalLevel, Message: "connection lost"})
+	require.EqualError(t, err, `fatal entry by module "db": connection lost`)
+}
@@ -0,0 +1,140 @@
+package modular
+
+import (
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// EventType defines the type of a change of the module tree
+type EventType int
+
+const (
+	// EventModuleCreated denotes that a module has been created
+	EventModuleCreated EventType = iota
+	// EventModuleRemoved denotes that a module has been removed
+	EventModuleRemoved
+	// EventLevelChanged denotes that the level of a module has changed
+	EventLevelChanged
+)
+
+func (et EventType) String() string {
+	switch et {
+	case EventModuleCreated:
+		return "created"
+	case EventModuleRemoved:
+		return "removed"
+	case EventLevelChanged:
+		return "level_changed"
+	}
+	return "unknown"
+}
+
+// EventSource defines what caused a change of the module tree
+type EventSource string
+
+const (
+	// EventSourceSetLevel denotes a change caused by calling SetLevel or SetLevelBy on the module
+	EventSourceSetLevel EventSource = "set_level"
+	// EventSourceParent denotes a level change propagated from a parent's SetLevel
+	EventSourceParent EventSource = "parent"
+	// EventSourceLevelRule denotes a level change caused by a level rule
+	EventSourceLevelRule EventSource = "level_rule"
+	// EventSourceCreateChild denotes a module created by CreateChild or GetOrCreateChild
+	EventSourceCreateChild EventSource = "create_child"
+	// EventSourceRemoveChild denotes a module removed by RemoveChild
+	EventSourceRemoveChild EventSource = "remove_child"
+	// EventSourceOverride denotes a level change caused by a level override taking effect or ending,
+	// see SetLevelFor. The override applies to descendants of the module as well, which are not reported.
+	EventSourceOverride EventSource = "override"
+)
+
+// Event describes a change of the module tree.
+// For created modules, NewLevel holds the initial level, for removed modules OldLevel holds the last level.
+// Actor and Reason are set for level changes caused by SetLevelBy.
+type Event struct {
+	Type     EventType
+	Module   string
+	OldLevel logrus.Level
+	NewLevel logrus.Level
+	Source   EventSource
+	Actor    string
+	Reason   string
+}
+
+// subscriber holds a handler registered through Subscribe
+type subscriber struct {
+	id      uint64
+	handler func(event Event)
+}
+
+// subscribers holds the handlers of a root logger
+type subscribers struct {
+	mutex    sync.Mutex
+	nextID   uint64
+	handlers []subscriber
+}
+
+func (lr *loggerRoot) Subscribe(handler func(event Event)) (unsubscribe func()) {
+	if handler == nil {
+		return func() {}
+	}
+
+	lr.subscribers.mutex.Lock()
+	defer lr.subscribers.mutex.Unlock()
+
+	id := lr.subscribers.nextID
+	lr.subscribers.nextID++
+	lr.subscribers.handlers = append(lr.subscribers.handlers, subscriber{id: id, handler: handler})
+
+	return func() {
+		lr.subscribers.mutex.Lock()
+		defer lr.subscribers.mutex.Unlock()
+
+		for i, s := range lr.subscribers.handlers {
+			if s.id == id {
+				lr.subscribers.handlers = append(lr.subscribers.handlers[:i:i], lr.subscribers.handlers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// publish passes the events to all subscribers, in the order they subscribed
+func (lr *loggerRoot) publish(events []Event) {
+	if len(events) == 0 {
+		return
+	}
+
+	lr.subscribers.mutex.Lock()
+	handlers := append([]subscriber(nil), lr.subscribers.handlers...)
+	lr.subscribers.mutex.Unlock()
+
+	for _, event := range events {
+		for _, s := range handlers {
+			s.handler(event)
+		}
+	}
+}
+
+// publish passes the events to the subscribers of the associated root.
+// Must not be called while holding a module's mutex, so handlers may modify the tree.
+func (lm *loggerModule) publish(events []Event) {
+	if root, ok := lm.root.(interface{ publish(events []Event) }); ok {
+		root.publish(events)
+	}
+}
+
+// createdEvents returns the events for the given created modules
+func createdEvents(modules []*loggerModule) []Event {
+	events := make([]Event, len(modules))
+	for i, module := range modules {
+		events[i] = Event{
+			Type:     EventModuleCreated,
+			Module:   module.name,
+			NewLevel: module.getOwnLevel(),
+			Source:   EventSourceCreateChild,
+		}
+	}
+	return events
+}
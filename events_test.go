@@ -0,0 +1,154 @@
+package modular
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventType_String(t *testing.T) {
+	require.EqualValues(t, "created", EventModuleCreated.String())
+	require.EqualValues(t, "removed", EventModuleRemoved.String())
+	require.EqualValues(t, "level_changed", EventLevelChanged.String())
+	require.EqualValues(t, "unknown", EventType(-1).String())
+}
+
+func TestLoggerRoot_Subscribe(t *testing.T) {
+	rl := NewRootLogger(logrus.New())
+
+	var events []Event
+	unsubscribe := rl.Subscribe(func(event Event) {
+		events = append(events, event)
+	})
+	// nil handlers are ignored
+	rl.Subscribe(nil)()
+
+	// Intermediate modules are reported as well
+	rl.GetOrCreateChild("db.query", logrus.WarnLevel)
+	require.EqualValues(t, []Event{
+		{Type: EventModuleCreated, Module: "db", NewLevel: logrus.WarnLevel, Source: EventSourceCreateChild},
+		{Type: EventModuleCreated, Module: "db.query", NewLevel: logrus.WarnLevel, Source: EventSourceCreateChild},
+	}, events)
+
+	// Existing modules are not reported
+	events = nil
+	rl.GetOrCreateChild("db.query", logrus.WarnLevel)
+	_, err := rl.CreateChild("db.query", logrus.WarnLevel)
+	require.Error(t, err)
+	require.Empty(t, events)
+
+	// Level changes are reported with their source, unchanged levels are not
+	db, err := rl.GetChild("db")
+	require.NoError(t, err)
+	db.SetLevel(logrus.DebugLevel)
+	db.SetLevel(logrus.DebugLevel)
+	require.EqualValues(t, []Event{
+		{Type: EventLevelChanged, Module: "db", OldLevel: logrus.WarnLevel, NewLevel: logrus.DebugLevel, Source: EventSourceSetLevel},
+		{Type: EventLevelChanged, Module: "db.query", OldLevel: logrus.WarnLevel, NewLevel: logrus.DebugLevel, Source: EventSourceParent},
+	}, events)
+
+	events = nil
+	rl.AddLevelRule(mustLevelRule(t, "*.query=error"))
+	require.EqualValues(t, []Event{
+		{Type: EventLevelChanged, Module: "db.query", OldLevel: logrus.DebugLevel, NewLevel: logrus.ErrorLevel, Source: EventSourceLevelRule},
+	}, events)
+
+	events = nil
+	require.NoError(t, rl.RemoveChild("db"))
+	require.EqualValues(t, []Event{
+		{Type: EventModuleRemoved, Module: "db", OldLevel: logrus.DebugLevel, Source: EventSourceRemoveChild},
+		{Type: EventModuleRemoved, Module: "db.query", OldLevel: logrus.ErrorLevel, Source: EventSourceRemoveChild},
+	}, events)
+
+	// No events after unsubscribing
+	events = nil
+	unsubscribe()
+	unsubscribe()
+	rl.GetOrCreateChild("http", logrus.InfoLevel)
+	require.Empty(t, events)
+}
+
+func TestLoggerRoot_Subscribe_Overrides(t *testing.T) {
+	clock := &fixedClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+	rl, err := New(logrus.New(), WithLevel(logrus.InfoLevel), WithClock(clock))
+	require.NoError(t, err)
+	db := rl.GetOrCreateChild("db", logrus.InfoLevel)
+
+	var events []Event
+	rl.Subscribe(func(event Event) {
+		events = append(events, event)
+	})
+
+	// Overrides are reported when taking effect and when cancelled
+	override, err := db.SetLevelFor(logrus.DebugLevel, time.Minute)
+	require.NoError(t, err)
+	_, err = db.SetLevelFor(logrus.TraceLevel, time.Hour)
+	require.NoError(t, err)
+	override.Cancel()
+	require.EqualValues(t, []Event{
+		{Type: EventLevelChanged, Module: "db", OldLevel: logrus.InfoLevel, NewLevel: logrus.DebugLevel, Source: EventSourceOverride},
+		{Type: EventLevelChanged, Module: "db", OldLevel: logrus.DebugLevel, NewLevel: logrus.TraceLevel, Source: EventSourceOverride},
+	}, events)
+
+	// ... and when expired
+	events = nil
+	clock.now = clock.now.Add(time.Hour)
+	require.EqualValues(t, logrus.InfoLevel, db.GetLevel())
+	require.EqualValues(t, []Event{
+		{Type: EventLevelChanged, Module: "db", OldLevel: logrus.TraceLevel, NewLevel: logrus.InfoLevel, Source: EventSourceOverride},
+	}, events)
+
+	// Overrides not changing the level are not reported
+	events = nil
+	override, err = db.SetLevelFor(logrus.InfoLevel, time.Minute)
+	require.NoError(t, err)
+	override.Cancel()
+	require.Empty(t, events)
+}
+
+func TestLoggerRoot_Subscribe_Overrides_Expiry(t *testing.T) {
+	rl := NewRootLogger(logrus.New())
+	db := rl.GetOrCreateChild("db", logrus.InfoLevel)
+
+	ended := make(chan Event, 1)
+	rl.Subscribe(func(event Event) {
+		if event.NewLevel == logrus.InfoLevel {
+			ended <- event
+		}
+	})
+
+	// Overrides are reported once expired, without reading the level
+	_, err := db.SetLevelFor(logrus.DebugLevel, 10*time.Millisecond)
+	require.NoError(t, err)
+	select {
+	case event := <-ended:
+		require.EqualValues(t, Event{Type: EventLevelChanged, Module: "db", OldLevel: logrus.DebugLevel, NewLevel: logrus.InfoLevel, Source: EventSourceOverride}, event)
+	case <-time.After(time.Second):
+		require.Fail(t, "override expiry not reported")
+	}
+}
+
+func TestLoggerRoot_Subscribe_ModifyTree(t *testing.T) {
+	rl := NewRootLogger(logrus.New())
+
+	// Handlers may modify the tree without deadlocking
+	rl.Subscribe(func(event Event) {
+		if event.Type == EventModuleCreated && event.Module == "db" {
+			rl.GetOrCreateChild("db.audit", logrus.InfoLevel).SetLevel(logrus.ErrorLevel)
+		}
+	})
+	rl.GetOrCreateChild("db", logrus.InfoLevel)
+
+	audit, err := rl.GetChild("db.audit")
+	require.NoError(t, err)
+	require.EqualValues(t, logrus.ErrorLevel, audit.GetLevel())
+}
+
+// mustLevelRule parses the given level rule, failing the test on error
+func mustLevelRule(t *testing.T, rule string) LevelRule {
+	levelRule, err := ParseLevelRule(rule)
+	require.NoError(t, err)
+	return levelRule
+}
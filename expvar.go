@@ -0,0 +1,91 @@
+package modular
+
+import (
+	"expvar"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// expvarMutex guards the check for existing variables in PublishExpvar, as expvar.Publish panics on duplicates
+var expvarMutex sync.Mutex
+
+// expvarModule defines the representation of a module published through expvar
+type expvarModule struct {
+	Level      string                  `json:"level"`
+	Emitted    map[string]uint64       `json:"emitted"`
+	Suppressed map[string]uint64       `json:"suppressed"`
+	Dropped    map[string]uint64       `json:"dropped"`
+	Children   map[string]expvarModule `json:"children,omitempty"`
+}
+
+func (lr *loggerRoot) PublishExpvar(name string) error {
+	if name == "" {
+		return ErrInvalidExpvarName
+	}
+
+	return publishExpvar(name, func() interface{} {
+		return newExpvarModule(lr)
+	})
+}
+
+// PublishExpvar publishes all mounted trees like RootLogger.PublishExpvar, keyed by prefix.
+// Trees mounted or unmounted later on are reflected in the published data. The registry stays published
+// and is never freed.
+func (r *Registry) PublishExpvar(name string) error {
+	if name == "" {
+		return ErrInvalidExpvarName
+	}
+
+	return publishExpvar(name, func() interface{} {
+		trees := make(map[string]expvarModule)
+		for _, prefix := range r.Prefixes() {
+			if root, ok := r.Get(prefix); ok {
+				trees[prefix] = newExpvarModule(root)
+			}
+		}
+		return trees
+	})
+}
+
+// publishExpvar publishes the value returned by fn under the given name, unless the name is taken
+func publishExpvar(name string, fn func() interface{}) error {
+	expvarMutex.Lock()
+	defer expvarMutex.Unlock()
+	if expvar.Get(name) != nil {
+		return ErrExpvarExists
+	}
+
+	expvar.Publish(name, expvar.Func(fn))
+	return nil
+}
+
+// newExpvarModule returns the current representation of the module and its children
+func newExpvarModule(module ModuleLogger) expvarModule {
+	stats := module.Stats()
+	em := expvarModule{
+		Level:      module.GetLevel().String(),
+		Emitted:    expvarCounters(stats.Emitted),
+		Suppressed: expvarCounters(stats.Suppressed),
+		Dropped:    expvarCounters(stats.Dropped),
+	}
+
+	children := module.GetChildren()
+	if len(children) > 0 {
+		em.Children = make(map[string]expvarModule, len(children))
+		for _, child := range children {
+			path := child.GetModulePath()
+			em.Children[path[len(path)-1]] = newExpvarModule(child)
+		}
+	}
+	return em
+}
+
+// expvarCounters returns the given counters keyed by level name
+func expvarCounters(counters map[logrus.Level]uint64) map[string]uint64 {
+	result := make(map[string]uint64, len(counters))
+	for level, value := range counters {
+		result[level.String()] = value
+	}
+	return result
+}
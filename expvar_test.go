@@ -0,0 +1,88 @@
+package modular
+
+import (
+	"bytes"
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+// expvarNames numbers the names returned by expvarName
+var expvarNames uint64
+
+// expvarName returns a name not published yet, as published variables remain for the lifetime of the process
+func expvarName(t *testing.T) string {
+	return fmt.Sprintf("%s_%d", t.Name(), atomic.AddUint64(&expvarNames, 1))
+}
+
+func TestLoggerRoot_PublishExpvar(t *testing.T) {
+	logger := logrus.New()
+	logger.Out = bytes.NewBufferString("")
+	rl := NewRootLogger(logger)
+	require.EqualValues(t, ErrInvalidExpvarName, rl.PublishExpvar(""))
+	name := expvarName(t)
+	require.NoError(t, rl.PublishExpvar(name))
+	require.EqualValues(t, ErrExpvarExists, rl.PublishExpvar(name))
+
+	read := func() expvarModule {
+		var em expvarModule
+		require.NoError(t, json.Unmarshal([]byte(expvar.Get(name).String()), &em))
+		return em
+	}
+
+	em := read()
+	require.EqualValues(t, "info", em.Level)
+	require.Empty(t, em.Children)
+
+	// Published data reflects later changes
+	query := rl.GetOrCreateChild("db.query", logrus.InfoLevel)
+	query.SetLevel(logrus.DebugLevel)
+	query.Error("failed")
+	query.Debug("debug")
+
+	em = read()
+	require.Contains(t, em.Children, "db")
+	require.Contains(t, em.Children["db"].Children, "query")
+	queryModule := em.Children["db"].Children["query"]
+	require.EqualValues(t, "debug", queryModule.Level)
+	require.EqualValues(t, 1, queryModule.Emitted["error"])
+	require.EqualValues(t, 1, queryModule.Emitted["debug"])
+	require.EqualValues(t, 0, queryModule.Suppressed["debug"])
+	require.EqualValues(t, 0, queryModule.Dropped["debug"])
+}
+
+func TestRegistry_PublishExpvar(t *testing.T) {
+	logger := logrus.New()
+	logger.Out = bytes.NewBufferString("")
+	tenantA := NewRootLogger(logger)
+	r := NewRegistry()
+	require.NoError(t, r.Mount("tenantA", tenantA))
+
+	name := expvarName(t)
+	require.EqualValues(t, ErrInvalidExpvarName, r.PublishExpvar(""))
+	require.NoError(t, r.PublishExpvar(name))
+	require.EqualValues(t, ErrExpvarExists, r.PublishExpvar(name))
+
+	read := func() map[string]expvarModule {
+		var trees map[string]expvarModule
+		require.NoError(t, json.Unmarshal([]byte(expvar.Get(name).String()), &trees))
+		return trees
+	}
+
+	tenantA.GetOrCreateChild("db", logrus.InfoLevel).Error("failed")
+	trees := read()
+	require.Len(t, trees, 1)
+	require.EqualValues(t, 1, trees["tenantA"].Children["db"].Emitted["error"])
+
+	// Published data reflects mounted trees
+	require.NoError(t, r.Mount("tenantB", NewRootLogger(logger)))
+	require.NoError(t, r.Unmount("tenantA"))
+	trees = read()
+	require.Len(t, trees, 1)
+	require.Contains(t, trees, "tenantB")
+}
@@ -0,0 +1,33 @@
+package modular
+
+// FatalAction defines how Fatal and Panic entries are handled once they have been logged
+type FatalAction int
+
+const (
+	// FatalActionDefault keeps the logrus behaviour: Fatal entries exit, Panic entries panic with the *logrus.Entry
+	FatalActionDefault FatalAction = iota
+	// FatalActionExit exits for both Fatal and Panic entries
+	FatalActionExit
+	// FatalActionError downgrades Fatal and Panic entries to Error and returns
+	FatalActionError
+	// FatalActionPanic panics with a *FatalError for both Fatal and Panic entries
+	FatalActionPanic
+)
+
+// DefaultExitCode defines the exit code used if a FatalPolicy does not define one
+const DefaultExitCode = 1
+
+// FatalPolicy defines how Fatal and Panic entries of a module are handled
+type FatalPolicy struct {
+	// Action defines what happens once the entry has been logged
+	Action FatalAction
+	// ExitCode defines the code to exit with, DefaultExitCode is used if zero
+	ExitCode int
+}
+
+func (fp FatalPolicy) exitCode() int {
+	if fp.ExitCode == 0 {
+		return DefaultExitCode
+	}
+	return fp.ExitCode
+}
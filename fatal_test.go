@@ -0,0 +1,12 @@
+package modular
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFatalPolicy_ExitCode(t *testing.T) {
+	require.EqualValues(t, DefaultExitCode, FatalPolicy{}.exitCode())
+	require.EqualValues(t, 3, FatalPolicy{ExitCode: 3}.exitCode())
+}
@@ -0,0 +1,113 @@
+package modular
+
+import (
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RecordedField defines the field marking entries written by a flight recorder
+const RecordedField = "recorded"
+
+// flightRecorder keeps the most recent entries of a module below the effective level in a ring buffer
+type flightRecorder struct {
+	mutex   sync.Mutex
+	entries []recordedEntry
+	next    int
+	full    bool
+}
+
+// recordedEntry holds an entry kept by a flight recorder and the counters of the module it originates from
+type recordedEntry struct {
+	entry *logrus.Entry
+	stats *moduleStats
+}
+
+func newFlightRecorder(size int) *flightRecorder {
+	return &flightRecorder{
+		entries: make([]recordedEntry, size),
+	}
+}
+
+// record adds the entry, overwriting the oldest entry if the buffer is full
+func (fr *flightRecorder) record(entry *logrus.Entry, stats *moduleStats) {
+	fr.mutex.Lock()
+	defer fr.mutex.Unlock()
+
+	if fr.full {
+		overwritten := fr.entries[fr.next]
+		overwritten.stats.incDropped(overwritten.entry.Level)
+	}
+	fr.entries[fr.next] = recordedEntry{entry: entry, stats: stats}
+	fr.next = (fr.next + 1) % len(fr.entries)
+	if fr.next == 0 {
+		fr.full = true
+	}
+}
+
+// drain removes and returns the buffered entries, oldest first
+func (fr *flightRecorder) drain() []recordedEntry {
+	fr.mutex.Lock()
+	defer fr.mutex.Unlock()
+
+	var entries []recordedEntry
+	if fr.full {
+		entries = append(entries, fr.entries[fr.next:]...)
+	}
+	entries = append(entries, fr.entries[:fr.next]...)
+
+	for i := range fr.entries {
+		fr.entries[i] = recordedEntry{}
+	}
+	fr.next = 0
+	fr.full = false
+
+	return entries
+}
+
+// discard removes the buffered entries, counting them as dropped
+func (fr *flightRecorder) discard() {
+	for _, recorded := range fr.drain() {
+		recorded.stats.incDropped(recorded.entry.Level)
+	}
+}
+
+func (lm *loggerModule) SetFlightRecorder(size int) error {
+	if size < 0 {
+		return ErrInvalidFlightRecorderSize
+	}
+
+	lm.settingsMutex.Lock()
+	defer lm.settingsMutex.Unlock()
+	lm.recorderSize = size
+	invalidateSettings()
+	return nil
+}
+
+func (lm *loggerModule) GetFlightRecorderSize() int {
+	return lm.resolveSettings().recorderSize
+}
+
+// flightRecorder returns the module's own flight recorder holding up to size entries, or nil if size is 0.
+// A recorder of another size is replaced, its entries are discarded.
+func (lm *loggerModule) flightRecorder(size int) *flightRecorder {
+	lm.recorderMutex.Lock()
+	defer lm.recorderMutex.Unlock()
+
+	if lm.recorder != nil && len(lm.recorder.entries) != size {
+		lm.recorder.discard()
+		lm.recorder = nil
+	}
+	if lm.recorder == nil && size > 0 {
+		lm.recorder = newFlightRecorder(size)
+	}
+	return lm.recorder
+}
+
+// flightRecorderOf returns the flight recorder of the given module if a flight recorder size is in effect
+func flightRecorderOf(moduleLogger ModuleLogger) *flightRecorder {
+	if lm, ok := moduleLogger.(interface{ flightRecorder(int) *flightRecorder }); ok {
+		return lm.flightRecorder(settingsOf(moduleLogger).recorderSize)
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package modular
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlightRecorder(t *testing.T) {
+	fr := newFlightRecorder(3)
+	require.Empty(t, fr.drain())
+
+	for _, msg := range []string{"1", "2"} {
+		fr.record(&logrus.Entry{Message: msg}, nil)
+	}
+	entries := fr.drain()
+	require.Len(t, entries, 2)
+	require.EqualValues(t, "1", entries[0].entry.Message)
+	require.EqualValues(t, "2", entries[1].entry.Message)
+	require.Empty(t, fr.drain())
+
+	// Oldest entries are overwritten
+	for _, msg := range []string{"1", "2", "3", "4", "5"} {
+		fr.record(&logrus.Entry{Message: msg}, nil)
+	}
+	entries = fr.drain()
+	require.Len(t, entries, 3)
+	require.EqualValues(t, "3", entries[0].entry.Message)
+	require.EqualValues(t, "4", entries[1].entry.Message)
+	require.EqualValues(t, "5", entries[2].entry.Message)
+	require.Empty(t, fr.drain())
+}
+
+func TestLoggerModule_SetFlightRecorder(t *testing.T) {
+	rl := NewRootLogger(logrus.New())
+	child := rl.GetOrCreateChild("child", logrus.InfoLevel)
+	require.EqualValues(t, 0, child.GetFlightRecorderSize())
+
+	require.EqualValues(t, ErrInvalidFlightRecorderSize, rl.SetFlightRecorder(-1))
+
+	// Inherited from the parent
+	require.NoError(t, rl.SetFlightRecorder(10))
+	require.EqualValues(t, 10, child.GetFlightRecorderSize())
+
+	require.NoError(t, child.SetFlightRecorder(5))
+	require.EqualValues(t, 5, child.GetFlightRecorderSize())
+	require.EqualValues(t, 10, rl.GetFlightRecorderSize())
+
+	require.NoError(t, child.SetFlightRecorder(0))
+	require.EqualValues(t, 10, child.GetFlightRecorderSize())
+	require.NoError(t, rl.SetFlightRecorder(0))
+	require.EqualValues(t, 0, child.GetFlightRecorderSize())
+}
+
+func TestLoggerBase_FlightRecorder(t *testing.T) {
+	buffer := bytes.NewBufferString("")
+	logger := logrus.New()
+	logger.Out = buffer
+	logger.Formatter = &logrus.JSONFormatter{}
+	clock := &fixedClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+	rl, err := New(logger, WithLevel(logrus.InfoLevel), WithClock(clock))
+	require.NoError(t, err)
+	logger.SetLevel(logrus.TraceLevel)
+
+	db := rl.GetOrCreateChild("db", logrus.InfoLevel)
+	query := db.GetOrCreateChild("query", logrus.InfoLevel)
+	http := rl.GetOrCreateChild("http", logrus.InfoLevel)
+	require.NoError(t, db.SetFlightRecorder(2))
+
+	// Entries below the level are recorded, not written
+	query.WithField("n", 1).Debug("first")
+	clock.now = clock.now.Add(time.Second)
+	query.Debug("second")
+	db.Debug("third")
+	http.Debug("other")
+	require.Empty(t, buffer.Bytes())
+
+	// Entries at the level do not flush
+	db.Info("info")
+	require.EqualValues(t, 1, strings.Count(buffer.String(), "\n"))
+	buffer.Reset()
+
+	// Error entries flush the module's own recorder first
+	query.Error("failed")
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	require.Len(t, lines, 3)
+
+	var entries []map[string]interface{}
+	for _, line := range lines {
+		var entry map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		entries = append(entries, entry)
+	}
+	require.EqualValues(t, "first", entries[0]["msg"])
+	require.EqualValues(t, "debug", entries[0]["level"])
+	require.EqualValues(t, "db.query", entries[0]["module"])
+	require.EqualValues(t, true, entries[0][RecordedField])
+	require.EqualValues(t, 1, entries[0]["n"])
+	require.EqualValues(t, "2020-01-01T00:00:00Z", entries[0]["time"])
+	require.EqualValues(t, "second", entries[1]["msg"])
+	require.EqualValues(t, "2020-01-01T00:00:01Z", entries[1]["time"])
+	require.EqualValues(t, "failed", entries[2]["msg"])
+	require.NotContains(t, entries[2], RecordedField)
+
+	// Entries of other modules are kept, the parent's entry is written with its own errors
+	require.NotContains(t, buffer.String(), "third")
+	buffer.Reset()
+	db.Error("failed")
+	require.EqualValues(t, 2, strings.Count(buffer.String(), "\n"))
+	require.Contains(t, buffer.String(), "third")
+
+	// The recorder is empty after flushing
+	buffer.Reset()
+	db.Error("failed")
+	require.EqualValues(t, 1, strings.Count(buffer.String(), "\n"))
+
+	// Modules which do not use a recorder do not record
+	buffer.Reset()
+	query.Debug("kept")
+	http.Error("failed")
+	db.Error("failed")
+	require.EqualValues(t, 2, strings.Count(buffer.String(), "\n"))
+	require.NotContains(t, buffer.String(), "kept")
+	require.NotContains(t, buffer.String(), "other")
+}
+
+func TestLoggerBase_FlightRecorder_LoggerLevel(t *testing.T) {
+	buffer := bytes.NewBufferString("")
+	logger := logrus.New()
+	logger.Out = buffer
+	rl := NewRootLogger(logger)
+	logger.SetLevel(logrus.InfoLevel)
+	require.NoError(t, rl.SetFlightRecorder(10))
+
+	// Recorded entries below the level of the logrus.Logger are dropped when flushing
+	rl.Debug("recorded")
+	rl.Error("failed")
+	require.EqualValues(t, 1, strings.Count(buffer.String(), "\n"))
+	require.NotContains(t, buffer.String(), "recorded")
+
+	// Each entry is counted once
+	stats := rl.Stats()
+	require.EqualValues(t, 0, stats.Suppressed[logrus.DebugLevel])
+	require.EqualValues(t, 1, stats.Dropped[logrus.DebugLevel])
+	require.EqualValues(t, 0, stats.Emitted[logrus.DebugLevel])
+	require.EqualValues(t, 1, stats.Emitted[logrus.ErrorLevel])
+
+	// Root loggers created by New write them regardless of the logger's level
+	buffer.Reset()
+	rl, err := New(logger, WithLevel(logrus.InfoLevel))
+	require.NoError(t, err)
+	require.NoError(t, rl.SetFlightRecorder(10))
+	rl.Debug("recorded")
+	rl.Error("failed")
+	require.EqualValues(t, 2, strings.Count(buffer.String(), "\n"))
+	require.Contains(t, buffer.String(), "recorded")
+	require.EqualValues(t, 1, rl.Stats().Emitted[logrus.DebugLevel])
+}
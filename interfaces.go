@@ -1,6 +1,11 @@
 package modular
 
-import "github.com/sirupsen/logrus"
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
 
 // Logger defines the baseline logger interface
 type Logger interface {
@@ -10,6 +15,9 @@ type Logger interface {
 	WithFields(fields logrus.Fields) Logger
 	// WithError extends the current logger's fields with an error field and returns a new logger
 	WithError(err error) Logger
+	// WithContext returns a new logger carrying the given context, which is passed on to entries
+	// and may raise levels for single requests, see ContextWithLevel
+	WithContext(ctx context.Context) Logger
 
 	Debugf(format string, args ...interface{})
 	Infof(format string, args ...interface{})
@@ -48,22 +56,90 @@ type ModuleLogger interface {
 
 	// GetModuleName returns the (full) module name
 	GetModuleName() string
+	// GetModulePath returns the segments of the (full) module name
+	GetModulePath() []string
 
 	// SetLevel sets the module's log level to the given level and recursively propagates this change
-	// to all children.
+	// to all children. Children matched by a level rule keep the level of the rule.
 	SetLevel(level logrus.Level)
-	// GetLevel returns the module's log level
+	// SetLevelBy sets the module's level like SetLevel and records who changed it and why
+	// in the root's audit history. The change is also logged through the AuditModuleName module at info level,
+	// regardless of the module's level.
+	SetLevelBy(level logrus.Level, actor, reason string)
+	// GetLevel returns the module's log level, taking level overrides into account
 	GetLevel() logrus.Level
+	// SetLevelFor temporarily overrides the level of the module and all children for the given duration.
+	// Once the override expires or is cancelled, the previous override or, if there is none,
+	// the module's own level applies again. Expiry is based on the root's clock.
+	SetLevelFor(level logrus.Level, duration time.Duration) (LevelOverride, error)
+	// GetLevelOverrides returns the active overrides set on the module, the most recent one being in effect
+	GetLevelOverrides() []LevelOverride
+
+	// SetReportCaller enables or disables reporting of the caller's file, function and line
+	// for the module and all children which do not configure caller reporting themselves.
+	SetReportCaller(enabled bool)
+	// GetReportCaller returns whether the caller is reported, either configured or inherited from the parent
+	GetReportCaller() bool
+
+	// SetFatalPolicy sets how Fatal and Panic entries are handled for the module and all children
+	// which do not configure a policy themselves.
+	SetFatalPolicy(policy FatalPolicy)
+	// GetFatalPolicy returns the policy for Fatal and Panic entries, either configured or inherited from the parent
+	GetFatalPolicy() FatalPolicy
+
+	// SetRedactionRules sets the rules redacting field values and messages of entries logged by the module and all children.
+	// Children apply the rules of all of their ancestors in addition to their own.
+	SetRedactionRules(rules ...RedactionRule)
+	// GetRedactionRules returns the redaction rules in effect, the rules of the topmost ancestor first
+	GetRedactionRules() []RedactionRule
+
+	// SetRevealSecrets enables or disables rendering the wrapped values of Secret and Hashed fields
+	// for the module and all children which do not configure it themselves. Disabled by default.
+	SetRevealSecrets(enabled bool)
+	// GetRevealSecrets returns whether Secret and Hashed fields are revealed, either configured or inherited from the parent
+	GetRevealSecrets() bool
+
+	// SetErrorExpansion sets how errors passed through WithError are expanded into additional fields
+	// for the module and all children which do not configure it themselves. Disabled by default.
+	SetErrorExpansion(expansion ErrorExpansion)
+	// GetErrorExpansion returns how errors are expanded, either configured or inherited from the parent
+	GetErrorExpansion() ErrorExpansion
+
+	// SetStackTracePolicy sets whether stack traces are attached to entries of the module and all children
+	// which do not configure a policy themselves. Disabled by default.
+	SetStackTracePolicy(policy StackTracePolicy)
+	// GetStackTracePolicy returns the stack trace policy, either configured or inherited from the parent
+	GetStackTracePolicy() StackTracePolicy
+
+	// SetFlightRecorder enables a flight recorder for the module and all children which do not configure one
+	// themselves. Each module keeps up to size of its own entries below the effective level in memory instead
+	// of dropping them and writes them ahead of its next Error, Fatal or Panic entry, entries of other modules
+	// are not written. A size of 0 removes the module's setting.
+	// Unless the root logger was created by New, entries are still filtered by the level of the underlying
+	// logrus.Logger when written, which must be set to the most verbose level recorded entries should be
+	// written at.
+	SetFlightRecorder(size int) error
+	// GetFlightRecorderSize returns the size of the flight recorder in effect, either configured or inherited
+	// from the parent, or 0 if there is none
+	GetFlightRecorderSize() int
+
+	// Stats returns a snapshot of the module's entry counters
+	Stats() Stats
 
 	// GetRoot returns the associated RootLogger
 	GetRoot() RootLogger
 
+	// GetChildren returns the direct children, ordered by name
+	GetChildren() []ModuleLogger
 	// GetChild returns the child with the given name
 	GetChild(moduleName string) (ModuleLogger, error)
 	// CreateChild creates a child with the given name
 	CreateChild(moduleName string, defaultLevel logrus.Level) (ModuleLogger, error)
-	// GetOrCreateChild tries returns an existing child or creates it, if it is missing
+	// GetOrCreateChild tries returns an existing child or creates it, if it is missing.
+	// Returns nil if the module name is invalid.
 	GetOrCreateChild(moduleName string, defaultLevel logrus.Level) ModuleLogger
+	// RemoveChild removes the child with the given name and all of its descendants from the tree
+	RemoveChild(moduleName string) error
 }
 
 // RootLogger defines the interface implemented by a root logger
@@ -78,4 +154,102 @@ type RootLogger interface {
 	// SetModuleField sets the module field.
 	// Sets field to DefaultModuleField if empty string is passed in.
 	SetModuleField(field string)
+	// GetModuleFieldStyle returns how the module name is represented in log entries
+	GetModuleFieldStyle() ModuleFieldStyle
+	// SetModuleFieldStyle sets how the module name is represented in log entries
+	SetModuleFieldStyle(style ModuleFieldStyle)
+	// GetModuleFieldSeparator returns the separator used when rendering the module name
+	GetModuleFieldSeparator() string
+	// SetModuleFieldSeparator sets the separator used when rendering the module name, i.e. "/" or "::".
+	// Falls back to the module separator if empty string is passed in.
+	SetModuleFieldSeparator(separator string)
+
+	// GetSeparator returns the separator between module name segments
+	GetSeparator() string
+	// SetSeparator sets the separator between module name segments, i.e. "/" for Go import paths.
+	// The separator can only be changed as long as no children have been created.
+	SetSeparator(separator string) error
+
+	// ForPackage returns the module logger for the calling package, creating it with the root's level if it is missing.
+	// The module name is derived from the package's import path by applying the package rules,
+	// i.e. "github.com/acme/svc/internal/db" becomes "svc.internal.db" with StripPackagePrefix("github.com/acme").
+	// Each path element becomes one segment, dots within path elements are replaced by '_' and path elements
+	// exceeding MaxModuleDepth or MaxModuleNameLength are dropped.
+	// Returns the root logger itself if the rules strip the whole import path or no module can be created for it.
+	ForPackage() ModuleLogger
+	// SetPackageRules sets the rules used by ForPackage, replacing DefaultPackageRules
+	SetPackageRules(rules ...PackageRule)
+
+	// RegisterShutdownHandler registers a handler which is run before exiting due to a Fatal or Panic entry
+	RegisterShutdownHandler(handler func())
+	// Exit runs the registered shutdown handlers and exits through the underlying logrus.Logger
+	Exit(code int)
+
+	// AddHook adds a hook which is fired for entries of the module tree only,
+	// in addition to the hooks of the underlying logrus.Logger
+	AddHook(hook logrus.Hook)
+	// GetHooks returns a copy of the hooks fired for entries of the module tree
+	GetHooks() logrus.LevelHooks
+
+	// Alias remaps module names resolved through the root logger: with Alias("grpc", "third_party.grpc"),
+	// GetOrCreateChild("grpc.transport") creates "third_party.grpc.transport".
+	// The alias matching the longest prefix of a module name applies, an existing alias for from is replaced.
+	// Returns ErrInvalidAlias if to equals from or lies within it.
+	Alias(from, to string) error
+	// RemoveAlias removes the alias for the given module name
+	RemoveAlias(from string)
+	// GetAliases returns a copy of all aliases, mapping from to to
+	GetAliases() map[string]string
+
+	// SetLevelRules replaces the level rules and applies them to all existing modules.
+	// Rules are evaluated in order, the last matching rule wins. Modules created later on
+	// start out at the level of the matching rule, rather than the passed default level.
+	SetLevelRules(rules ...LevelRule)
+	// AddLevelRule appends a level rule and applies the rules to all existing modules
+	AddLevelRule(rule LevelRule)
+	// GetLevelRules returns a copy of the level rules
+	GetLevelRules() []LevelRule
+	// MatchLevelRule returns the rule determining the level of the module with the given full name, if any
+	MatchLevelRule(moduleName string) (LevelRule, bool)
+
+	// GetClock returns the clock providing entry timestamps
+	GetClock() Clock
+	// SetClock sets the clock providing entry timestamps.
+	// Falls back to SystemClock if nil is passed in.
+	SetClock(clock Clock)
+
+	// SetHashSalt sets the salt used to render Hashed fields.
+	// Falls back to a random salt generated once per process if an empty salt is passed in.
+	SetHashSalt(salt []byte)
+
+	// PublishExpvar publishes the module tree with levels and entry counters through expvar under the given name.
+	// The published data is computed whenever it is read, i.e. through /debug/vars.
+	// As expvar offers no way to remove variables, a published root stays published and is never freed.
+	PublishExpvar(name string) error
+
+	// Subscribe registers a handler which is called for every change of the module tree: modules being
+	// created or removed and level changes caused by SetLevel, level rules or level overrides taking effect
+	// and ending, see EventSourceOverride. Handlers are called synchronously after the change, in the order
+	// they subscribed. Overrides expiring according to a clock other than SystemClock are reported once the
+	// level is read.
+	// The returned function removes the handler.
+	Subscribe(handler func(event Event)) (unsubscribe func())
+
+	// GetAuditRecords returns the recorded level changes affecting the given module, that is changes made
+	// to the module itself or to one of its ancestors, oldest first. Returns all records for an empty name.
+	GetAuditRecords(moduleName string) []AuditRecord
+	// SetAuditHistorySize sets the number of audit records kept, DefaultAuditHistorySize by default
+	SetAuditHistorySize(size int) error
+}
+
+// LevelOverride defines the interface of a temporary level override, as created by ModuleLogger.SetLevelFor
+type LevelOverride interface {
+	// GetLevel returns the level set by the override
+	GetLevel() logrus.Level
+	// GetExpiry returns the time at which the override expires
+	GetExpiry() time.Time
+	// IsActive reports whether the override has neither expired nor been cancelled
+	IsActive() bool
+	// Cancel cancels the override before it expires
+	Cancel()
 }
@@ -0,0 +1,189 @@
+package modular
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RegexpPatternPrefix marks level rule patterns which are regular expressions, i.e. "re:^cache\.(l1|l2)$"
+const RegexpPatternPrefix = "re:"
+
+// LevelRule assigns a level to all modules whose full name matches a pattern.
+//
+// Patterns are either globs or, if prefixed by RegexpPatternPrefix, regular expressions matched against
+// the full module name. Globs are matched segment by segment, "**" matching any number of segments,
+// including none, and all other segments being matched by path.Match, i.e. "*" matching exactly one segment:
+// "*.sql" matches "db.sql", "http.**" matches "http" and all of its descendants.
+type LevelRule struct {
+	Pattern string
+	Level   logrus.Level
+
+	regexp *regexp.Regexp
+	// mount is the prefix of rules added by Registry.ApplyLevelSpec, which match prefixed module names
+	mount string
+}
+
+// NewLevelRule creates a new level rule, validating the pattern and level
+func NewLevelRule(pattern string, level logrus.Level) (LevelRule, error) {
+	if !isValidLevel(level) {
+		return LevelRule{}, ErrInvalidLevel
+	}
+
+	rule := LevelRule{
+		Pattern: pattern,
+		Level:   level,
+	}
+
+	if strings.HasPrefix(pattern, RegexpPatternPrefix) {
+		re, err := regexp.Compile(strings.TrimPrefix(pattern, RegexpPatternPrefix))
+		if err != nil {
+			return LevelRule{}, ErrInvalidPattern
+		}
+		rule.regexp = re
+		return rule, nil
+	}
+
+	if pattern == "" {
+		return LevelRule{}, ErrInvalidPattern
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return LevelRule{}, ErrInvalidPattern
+	}
+
+	return rule, nil
+}
+
+// newModuleLevelRule returns a rule matching exactly the module with the given full name
+func newModuleLevelRule(moduleName string, level logrus.Level) LevelRule {
+	return LevelRule{
+		Pattern: strings.ReplaceAll(moduleName, `\`, `\\`),
+		Level:   level,
+	}
+}
+
+// ParseLevelRule parses a level rule in the form "pattern=level", i.e. "*.sql=debug"
+func ParseLevelRule(rule string) (LevelRule, error) {
+	idx := strings.LastIndex(rule, "=")
+	if idx < 0 {
+		return LevelRule{}, ErrInvalidLevelRule
+	}
+
+	level, err := logrus.ParseLevel(strings.TrimSpace(rule[idx+1:]))
+	if err != nil {
+		return LevelRule{}, ErrInvalidLevel
+	}
+
+	return NewLevelRule(strings.TrimSpace(rule[:idx]), level)
+}
+
+// String returns the level rule in the format understood by ParseLevelRule
+func (lr LevelRule) String() string {
+	return fmt.Sprintf("%s=%s", lr.Pattern, lr.Level.String())
+}
+
+// Matches reports whether the full module name matches the rule's pattern,
+// using the given separator between module name segments
+func (lr LevelRule) Matches(moduleName, separator string) bool {
+	if lr.mount != "" {
+		moduleName, separator = prefixedModuleName(lr.mount, moduleName), DefaultSeparator
+	}
+
+	if lr.regexp != nil {
+		return lr.regexp.MatchString(moduleName)
+	}
+
+	var segments []string
+	if moduleName != "" {
+		segments = strings.Split(moduleName, separator)
+	}
+	return matchGlob(strings.Split(lr.Pattern, separator), segments)
+}
+
+// matchGlob matches the module name segments against the pattern segments
+func matchGlob(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchGlob(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(segments) == 0 {
+		return false
+	}
+	if matched, _ := path.Match(pattern[0], segments[0]); !matched {
+		return false
+	}
+	return matchGlob(pattern[1:], segments[1:])
+}
+
+// isLevelPattern reports whether the module name of a level spec entry is a pattern, rather than a module name
+func isLevelPattern(moduleName string) bool {
+	return strings.HasPrefix(moduleName, RegexpPatternPrefix) || strings.ContainsAny(moduleName, "*?[")
+}
+
+func (lr *loggerRoot) SetLevelRules(rules ...LevelRule) {
+	lr.levelRulesMutex.Lock()
+	lr.levelRules = append([]LevelRule(nil), rules...)
+	lr.levelRulesMutex.Unlock()
+
+	lr.applyLevelRules()
+}
+
+func (lr *loggerRoot) AddLevelRule(rule LevelRule) {
+	lr.levelRulesMutex.Lock()
+	lr.levelRules = append(lr.levelRules, rule)
+	lr.levelRulesMutex.Unlock()
+
+	lr.applyLevelRules()
+}
+
+func (lr *loggerRoot) GetLevelRules() []LevelRule {
+	lr.levelRulesMutex.Lock()
+	defer lr.levelRulesMutex.Unlock()
+
+	return append([]LevelRule(nil), lr.levelRules...)
+}
+
+func (lr *loggerRoot) MatchLevelRule(moduleName string) (LevelRule, bool) {
+	separator := lr.GetSeparator()
+
+	lr.levelRulesMutex.Lock()
+	defer lr.levelRulesMutex.Unlock()
+
+	for i := len(lr.levelRules) - 1; i >= 0; i-- {
+		if lr.levelRules[i].Matches(moduleName, separator) {
+			return lr.levelRules[i], true
+		}
+	}
+	return LevelRule{}, false
+}
+
+// applyLevelRules sets the level of all existing modules matched by a rule, without propagation to children
+func (lr *loggerRoot) applyLevelRules() {
+	var events []Event
+	lr.loggerModule.walk(func(lm *loggerModule) {
+		if rule, ok := lr.MatchLevelRule(lm.name); ok {
+			if oldLevel := lm.setOwnLevel(rule.Level); oldLevel != rule.Level {
+				events = append(events, Event{
+					Type:     EventLevelChanged,
+					Module:   lm.name,
+					OldLevel: oldLevel,
+					NewLevel: rule.Level,
+					Source:   EventSourceLevelRule,
+				})
+			}
+		}
+	})
+	lr.publish(events)
+}
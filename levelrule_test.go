@@ -0,0 +1,163 @@
+package modular
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLevelRule_Matches(t *testing.T) {
+	tests := []struct {
+		pattern string
+		matches []string
+		misses  []string
+	}{
+		{"db", []string{"db"}, []string{"db.sql", "dbx", ""}},
+		{"*.sql", []string{"db.sql", "cache.sql"}, []string{"sql", "db.x.sql", "db.sql.x"}},
+		{"http.**", []string{"http", "http.server", "http.server.tls"}, []string{"https", "grpc.http"}},
+		{"**.sql", []string{"sql", "db.sql", "a.b.sql"}, []string{"db.sqlx"}},
+		{"a.**.z", []string{"a.z", "a.b.z", "a.b.c.z"}, []string{"a.b", "b.z"}},
+		{"cache.l?", []string{"cache.l1", "cache.l2"}, []string{"cache.l10"}},
+		{"**", []string{"", "a", "a.b"}, nil},
+		{`re:^cache\.(l1|l2)$`, []string{"cache.l1", "cache.l2"}, []string{"cache.l3", "cache.l1.x"}},
+	}
+
+	for _, test := range tests {
+		rule, err := NewLevelRule(test.pattern, logrus.DebugLevel)
+		require.NoError(t, err)
+		for _, name := range test.matches {
+			require.True(t, rule.Matches(name, "."), "%q should match %q", test.pattern, name)
+		}
+		for _, name := range test.misses {
+			require.False(t, rule.Matches(name, "."), "%q should not match %q", test.pattern, name)
+		}
+	}
+
+	// Separator is taken into account
+	rule, err := NewLevelRule("github.com/*/db", logrus.DebugLevel)
+	require.NoError(t, err)
+	require.True(t, rule.Matches("github.com/acme/db", "/"))
+}
+
+func TestParseLevelRule(t *testing.T) {
+	rule, err := ParseLevelRule("http.** = warn")
+	require.NoError(t, err)
+	require.EqualValues(t, "http.**", rule.Pattern)
+	require.EqualValues(t, logrus.WarnLevel, rule.Level)
+	require.EqualValues(t, "http.**=warning", rule.String())
+
+	rule, err = ParseLevelRule(`re:^a=b$=trace`)
+	require.NoError(t, err)
+	require.EqualValues(t, `re:^a=b$`, rule.Pattern)
+	require.True(t, rule.Matches("a=b", "."))
+
+	_, err = ParseLevelRule("db")
+	require.EqualError(t, err, ErrInvalidLevelRule.Error())
+	_, err = ParseLevelRule("db=verbose")
+	require.EqualError(t, err, ErrInvalidLevel.Error())
+	_, err = ParseLevelRule("re:(=debug")
+	require.EqualError(t, err, ErrInvalidPattern.Error())
+	_, err = ParseLevelRule("db.[=debug")
+	require.EqualError(t, err, ErrInvalidPattern.Error())
+	_, err = ParseLevelRule("=debug")
+	require.EqualError(t, err, ErrInvalidPattern.Error())
+}
+
+func TestLoggerRoot_SetLevelRules(t *testing.T) {
+	rl := NewRootLogger(logrus.New())
+	dbSQL := rl.GetOrCreateChild("db.sql", logrus.InfoLevel)
+	http := rl.GetOrCreateChild("http", logrus.InfoLevel)
+	httpServer := rl.GetOrCreateChild("http.server", logrus.InfoLevel)
+	cache := rl.GetOrCreateChild("cache", logrus.InfoLevel)
+
+	mustRule := func(rule string) LevelRule {
+		levelRule, err := ParseLevelRule(rule)
+		require.NoError(t, err)
+		return levelRule
+	}
+
+	rl.SetLevelRules(mustRule("*.sql=debug"), mustRule("http.**=warn"), mustRule("http.server=error"))
+	require.Len(t, rl.GetLevelRules(), 3)
+
+	// Existing modules are updated, unmatched modules keep their level
+	require.EqualValues(t, logrus.DebugLevel, dbSQL.GetLevel())
+	require.EqualValues(t, logrus.WarnLevel, http.GetLevel())
+	require.EqualValues(t, logrus.ErrorLevel, httpServer.GetLevel())
+	require.EqualValues(t, logrus.InfoLevel, cache.GetLevel())
+
+	// Last matching rule wins
+	rule, ok := rl.MatchLevelRule("http.server")
+	require.True(t, ok)
+	require.EqualValues(t, "http.server", rule.Pattern)
+	rule, ok = rl.MatchLevelRule("http.client")
+	require.True(t, ok)
+	require.EqualValues(t, "http.**", rule.Pattern)
+	_, ok = rl.MatchLevelRule("cache")
+	require.False(t, ok)
+
+	// Modules created later on start out at the rule's level
+	child, err := rl.CreateChild("cache.sql", logrus.InfoLevel)
+	require.NoError(t, err)
+	require.EqualValues(t, logrus.DebugLevel, child.GetLevel())
+	child = rl.GetOrCreateChild("http.client.pool", logrus.InfoLevel)
+	require.EqualValues(t, logrus.WarnLevel, child.GetLevel())
+	child = rl.GetOrCreateChild("other", logrus.InfoLevel)
+	require.EqualValues(t, logrus.InfoLevel, child.GetLevel())
+
+	rl.AddLevelRule(mustRule(`re:^cache$=trace`))
+	require.EqualValues(t, logrus.TraceLevel, cache.GetLevel())
+	require.Len(t, rl.GetLevelRules(), 4)
+}
+
+func TestLevelSpec_Apply_Patterns(t *testing.T) {
+	rl := NewRootLogger(logrus.New())
+	dbSQL := rl.GetOrCreateChild("db.sql", logrus.InfoLevel)
+
+	spec, err := ParseLevelSpec(`*.sql=debug,re:^cache\.(l1|l2)$=trace`)
+	require.NoError(t, err)
+	require.NoError(t, spec.Apply(rl))
+	require.Len(t, rl.GetLevelRules(), 2)
+	require.EqualValues(t, logrus.DebugLevel, dbSQL.GetLevel())
+	require.EqualValues(t, logrus.TraceLevel, rl.GetOrCreateChild("cache.l2", logrus.InfoLevel).GetLevel())
+
+	err = LevelSpec{{Module: "re:(", Level: logrus.InfoLevel}}.Apply(rl)
+	require.True(t, errors.Is(err, ErrInvalidLevelSpec))
+	require.True(t, errors.Is(err, ErrInvalidPattern))
+}
+
+func TestLoggerRoot_LevelRules_SetLevel(t *testing.T) {
+	rl := NewRootLogger(logrus.New())
+	sql := rl.GetOrCreateChild("db.sql", logrus.InfoLevel)
+	conn := rl.GetOrCreateChild("db.sql.conn", logrus.InfoLevel)
+	db, err := rl.GetChild("db")
+	require.NoError(t, err)
+
+	rule, err := ParseLevelRule("*.sql=debug")
+	require.NoError(t, err)
+	rl.AddLevelRule(rule)
+	require.EqualValues(t, logrus.DebugLevel, sql.GetLevel())
+
+	var events []Event
+	rl.Subscribe(func(event Event) {
+		events = append(events, event)
+	})
+
+	// Propagated levels do not override matching rules, descendants are still updated
+	rl.SetLevel(logrus.WarnLevel)
+	require.EqualValues(t, logrus.WarnLevel, db.GetLevel())
+	require.EqualValues(t, logrus.DebugLevel, sql.GetLevel())
+	require.EqualValues(t, logrus.WarnLevel, conn.GetLevel())
+	matched, ok := rl.MatchLevelRule("db.sql")
+	require.True(t, ok)
+	require.EqualValues(t, matched.Level, sql.GetLevel())
+	for _, event := range events {
+		require.NotEqual(t, "db.sql", event.Module)
+	}
+
+	// Explicit changes still apply
+	sql.SetLevel(logrus.ErrorLevel)
+	require.EqualValues(t, logrus.ErrorLevel, sql.GetLevel())
+	require.EqualValues(t, logrus.ErrorLevel, conn.GetLevel())
+}
@@ -0,0 +1,108 @@
+package modular
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// LevelSpecEntry assigns a level to a module, the root module if Module is empty
+type LevelSpecEntry struct {
+	Module string
+	Level  logrus.Level
+}
+
+// LevelSpec is a list of module levels, applied in order
+type LevelSpec []LevelSpecEntry
+
+// ParseLevelSpec parses a comma separated list of "module=level" entries, i.e. "info,db=debug,http.**=warn".
+// Entries without a module name set the level of the root module, module names may be level rule patterns.
+func ParseLevelSpec(spec string) (LevelSpec, error) {
+	var levelSpec LevelSpec
+	for _, rawEntry := range strings.Split(spec, ",") {
+		rawEntry = strings.TrimSpace(rawEntry)
+		if rawEntry == "" {
+			continue
+		}
+
+		module, rawLevel := "", rawEntry
+		if idx := strings.LastIndex(rawEntry, "="); idx >= 0 {
+			module, rawLevel = strings.TrimSpace(rawEntry[:idx]), strings.TrimSpace(rawEntry[idx+1:])
+		}
+
+		level, err := logrus.ParseLevel(rawLevel)
+		if err != nil {
+			return nil, &LevelSpecError{Entry: rawEntry, Err: ErrInvalidLevel}
+		}
+
+		levelSpec = append(levelSpec, LevelSpecEntry{Module: module, Level: level})
+	}
+
+	return levelSpec, nil
+}
+
+// String returns the level spec in the format understood by ParseLevelSpec
+func (ls LevelSpec) String() string {
+	entries := make([]string, len(ls))
+	for i, entry := range ls {
+		if entry.Module == "" {
+			entries[i] = entry.Level.String()
+		} else {
+			entries[i] = fmt.Sprintf("%s=%s", entry.Module, entry.Level.String())
+		}
+	}
+	return strings.Join(entries, ",")
+}
+
+// Apply applies the level spec to the given module logger, creating missing modules.
+// Entries other than the one of the given module logger are added as level rules to the root logger,
+// so the last matching entry determines the level of existing modules as well as of modules created later on:
+// "info,*.sql=warn,db.sql=debug" sets "db.sql" to DebugLevel and all other "*.sql" modules to WarnLevel.
+// Entries whose module name is a pattern, i.e. "*.sql" or "re:^cache", are matched against full module names,
+// all others name a module relative to the given module logger, whose level is also propagated to its children.
+func (ls LevelSpec) Apply(moduleLogger ModuleLogger) error {
+	for _, entry := range ls {
+		if err := applyLevelSpecEntry(moduleLogger, entry, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyLevelSpecEntry applies a single level spec entry to the given module logger.
+// Patterns are matched against module names prefixed by mount, if not empty, as done by Registry.
+func applyLevelSpecEntry(moduleLogger ModuleLogger, entry LevelSpecEntry, mount string) error {
+	if entry.Module == "" {
+		moduleLogger.SetLevel(entry.Level)
+		return nil
+	}
+
+	if isLevelPattern(entry.Module) {
+		rule, err := NewLevelRule(entry.Module, entry.Level)
+		if err != nil {
+			return &LevelSpecError{Entry: entry.Module, Err: err}
+		}
+		rule.mount = mount
+		moduleLogger.GetRoot().AddLevelRule(rule)
+		return nil
+	}
+
+	if !isValidLevel(entry.Level) {
+		return &LevelSpecError{Entry: entry.Module, Err: ErrInvalidLevel}
+	}
+	child, err := moduleLogger.GetChild(entry.Module)
+	if err != nil && !errors.Is(err, ErrChildNotFound) {
+		return &LevelSpecError{Entry: entry.Module, Err: err}
+	}
+	if err != nil {
+		if child, err = moduleLogger.CreateChild(entry.Module, entry.Level); err != nil {
+			return &LevelSpecError{Entry: entry.Module, Err: err}
+		}
+	} else {
+		child.SetLevel(entry.Level)
+	}
+	moduleLogger.GetRoot().AddLevelRule(newModuleLevelRule(child.GetModuleName(), entry.Level))
+	return nil
+}
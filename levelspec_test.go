@@ -0,0 +1,102 @@
+package modular
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLevelSpec(t *testing.T) {
+	spec, err := ParseLevelSpec(" info, db=debug ,db.sql = warn,,")
+	require.NoError(t, err)
+	require.EqualValues(t, LevelSpec{
+		{Module: "", Level: logrus.InfoLevel},
+		{Module: "db", Level: logrus.DebugLevel},
+		{Module: "db.sql", Level: logrus.WarnLevel},
+	}, spec)
+	require.EqualValues(t, "info,db=debug,db.sql=warning", spec.String())
+
+	spec, err = ParseLevelSpec("")
+	require.NoError(t, err)
+	require.Empty(t, spec)
+
+	spec, err = ParseLevelSpec("db=verbose")
+	require.Nil(t, spec)
+	require.True(t, errors.Is(err, ErrInvalidLevelSpec))
+	require.True(t, errors.Is(err, ErrInvalidLevel))
+	require.EqualError(t, err, `Invalid level spec entry "db=verbose": Invalid level`)
+}
+
+func TestLevelSpec_Apply(t *testing.T) {
+	rl := NewRootLogger(logrus.New())
+	existing := rl.GetOrCreateChild("http", logrus.InfoLevel)
+
+	spec, err := ParseLevelSpec("error,db.sql=warn,db=debug,http=trace")
+	require.NoError(t, err)
+	require.NoError(t, spec.Apply(rl))
+
+	require.EqualValues(t, logrus.ErrorLevel, rl.GetLevel())
+	require.EqualValues(t, logrus.TraceLevel, existing.GetLevel())
+
+	// "db" propagates to its children, except those matched by an entry of their own
+	db, err := rl.GetChild("db")
+	require.NoError(t, err)
+	require.EqualValues(t, logrus.DebugLevel, db.GetLevel())
+	sql, err := rl.GetChild("db.sql")
+	require.NoError(t, err)
+	require.EqualValues(t, logrus.WarnLevel, sql.GetLevel())
+	query := db.GetOrCreateChild("query", logrus.InfoLevel)
+	db.SetLevel(logrus.DebugLevel)
+	require.EqualValues(t, logrus.DebugLevel, query.GetLevel())
+	require.EqualValues(t, logrus.WarnLevel, sql.GetLevel())
+
+	// Invalid module names
+	err = LevelSpec{{Module: "db..sql", Level: logrus.InfoLevel}}.Apply(rl)
+	require.True(t, errors.Is(err, ErrInvalidLevelSpec))
+	require.True(t, errors.Is(err, ErrInvalidModuleName))
+}
+
+func TestLevelSpec_Apply_LastEntryWins(t *testing.T) {
+	spec, err := ParseLevelSpec("info,*.sql=warn,db.sql=debug")
+	require.NoError(t, err)
+
+	// Modules existing before the spec is applied...
+	rl := NewRootLogger(logrus.New())
+	existingSQL := rl.GetOrCreateChild("db.sql", logrus.InfoLevel)
+	existingOther := rl.GetOrCreateChild("cache.sql", logrus.InfoLevel)
+	require.NoError(t, spec.Apply(rl))
+	require.EqualValues(t, logrus.DebugLevel, existingSQL.GetLevel())
+	require.EqualValues(t, logrus.WarnLevel, existingOther.GetLevel())
+
+	// ... and modules created later on end up at the same level
+	rl = NewRootLogger(logrus.New())
+	require.NoError(t, spec.Apply(rl))
+	require.NoError(t, rl.RemoveChild("db"))
+	require.EqualValues(t, logrus.DebugLevel, rl.GetOrCreateChild("db.sql", logrus.InfoLevel).GetLevel())
+	require.EqualValues(t, logrus.WarnLevel, rl.GetOrCreateChild("cache.sql", logrus.InfoLevel).GetLevel())
+
+	// Reversed, the pattern wins
+	spec, err = ParseLevelSpec("info,db.sql=debug,*.sql=warn")
+	require.NoError(t, err)
+	rl = NewRootLogger(logrus.New())
+	require.NoError(t, spec.Apply(rl))
+	sql, err := rl.GetChild("db.sql")
+	require.NoError(t, err)
+	require.EqualValues(t, logrus.WarnLevel, sql.GetLevel())
+	require.NoError(t, rl.RemoveChild("db"))
+	require.EqualValues(t, logrus.WarnLevel, rl.GetOrCreateChild("db.sql", logrus.InfoLevel).GetLevel())
+
+	// Explicit entries are relative to the module logger they are applied to
+	db := rl.GetOrCreateChild("db", logrus.InfoLevel)
+	require.NoError(t, LevelSpec{{Module: "query", Level: logrus.ErrorLevel}}.Apply(db))
+	require.EqualValues(t, "db.query=error", rl.GetLevelRules()[len(rl.GetLevelRules())-1].String())
+}
+
+func TestWithLevelSpec_LastEntryWins(t *testing.T) {
+	rl, err := New(logrus.New(), WithLevelSpec("info,*.sql=warn,db.sql=debug"))
+	require.NoError(t, err)
+	require.NoError(t, rl.RemoveChild("db"))
+	require.EqualValues(t, logrus.DebugLevel, rl.GetOrCreateChild("db.sql", logrus.InfoLevel).GetLevel())
+}
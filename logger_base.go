@@ -1,6 +1,22 @@
 package modular
 
-import "github.com/sirupsen/logrus"
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// CallerFileField defines the field holding the file of the caller, if caller reporting is enabled
+	CallerFileField = "file"
+	// CallerFuncField defines the field holding the function of the caller, if caller reporting is enabled
+	CallerFuncField = "func"
+	// CallerLineField defines the field holding the line of the caller, if caller reporting is enabled
+	CallerLineField = "line"
+)
 
 var _ Logger = (*loggerBase)(nil)
 
@@ -8,6 +24,7 @@ type loggerBase struct {
 	moduleLogger ModuleLogger
 
 	fields logrus.Fields
+	ctx    context.Context
 }
 
 func (lb *loggerBase) WithField(key string, value interface{}) Logger {
@@ -32,6 +49,15 @@ func (lb *loggerBase) WithFields(fields logrus.Fields) Logger {
 	return &loggerBase{
 		moduleLogger: lb.moduleLogger,
 		fields:       mergedFields,
+		ctx:          lb.ctx,
+	}
+}
+
+func (lb *loggerBase) WithContext(ctx context.Context) Logger {
+	return &loggerBase{
+		moduleLogger: lb.moduleLogger,
+		fields:       lb.fields,
+		ctx:          ctx,
 	}
 }
 
@@ -39,156 +65,336 @@ func (lb *loggerBase) WithError(err error) Logger {
 	return lb.WithField(logrus.ErrorKey, err)
 }
 
+// newEntry creates a new entry for the given level or returns nil if the level is disabled.
+// If the level is disabled but a flight recorder is in effect, the entry is returned without a Logger
+// and is recorded by log instead of being written.
+// newEntry must be called directly from the logging methods, as caller reporting relies on
+// a fixed stack depth.
 func (lb *loggerBase) newEntry(level logrus.Level) *logrus.Entry {
 	moduleLogger := lb.GetModuleLogger()
+	rootLogger := moduleLogger.GetRoot()
 	effectiveLevel := moduleLogger.GetLevel()
-	if effectiveLevel < level {
+	if lb.ctx != nil && effectiveLevel < level {
+		if contextLevel, ok := LevelFromContext(lb.ctx, moduleLogger.GetModuleName(), rootLogger.GetSeparator()); ok && contextLevel > effectiveLevel {
+			effectiveLevel = contextLevel
+		}
+	}
+	settings := settingsOf(moduleLogger)
+	recorded := effectiveLevel < level
+	if recorded && settings.recorderSize == 0 {
+		statsOf(moduleLogger).incSuppressed(level)
 		return nil
 	}
 
-	rootLogger := moduleLogger.GetRoot()
-	moduleFieldName := rootLogger.GetModuleField()
-
 	fields := make(logrus.Fields, len(lb.fields)+1)
-	fields[moduleFieldName] = moduleLogger.GetModuleName()
-	for fieldName, fieldValue := range lb.fields {
-		fields[fieldName] = fieldValue
+	addModuleFields(fields, rootLogger, moduleLogger)
+	if len(lb.fields) > 0 {
+		redaction := newRedaction(moduleLogger, settings)
+		for fieldName, fieldValue := range lb.fields {
+			fields[fieldName] = redaction.redact(fieldName, fieldValue)
+		}
+		if err, ok := lb.fields[logrus.ErrorKey].(error); ok {
+			for fieldName, fieldValue := range expandError(err, settings.errorExpansion) {
+				fields[fieldName] = redaction.redact(fieldName, fieldValue)
+			}
+		}
+	}
+
+	if settings.reportCaller {
+		// Skip newEntry and the logging method
+		if pc, file, line, ok := runtime.Caller(2); ok {
+			fields[CallerFileField] = file
+			fields[CallerLineField] = line
+			if fn := runtime.FuncForPC(pc); fn != nil {
+				fields[CallerFuncField] = unescapeFuncName(fn.Name())
+			}
+		}
+	}
+
+	if policy := settings.stackTracePolicy; policy.appliesTo(level) {
+		// Skip newEntry and the logging method
+		fields[StackTraceField] = captureStack(2, policy)
+	}
+
+	entry := &logrus.Entry{
+		Data:    fields,
+		Context: lb.ctx,
 	}
-	return &logrus.Entry{
-		Logger: rootLogger.GetLogger(),
-		Data:   fields,
+	if !recorded {
+		entry.Logger = rootLogger.GetLogger()
 	}
+	return entry
+}
+
+// log writes the entry with the given level and message, the message being redacted by the value rules in effect.
+// Fatal and Panic entries are handled according to the module's FatalPolicy.
+func (lb *loggerBase) log(entry *logrus.Entry, level logrus.Level, msg string) {
+	msg, _ = newRedaction(lb.GetModuleLogger(), settingsOf(lb.GetModuleLogger())).redactString(msg)
+	if entry.Logger == nil {
+		lb.record(entry, level, msg)
+		return
+	}
+	if level <= logrus.ErrorLevel {
+		lb.flush()
+	}
+
+	if level > logrus.FatalLevel {
+		lb.write(entry, level, msg)
+		return
+	}
+
+	moduleLogger := lb.GetModuleLogger()
+	policy := moduleLogger.GetFatalPolicy()
+
+	switch policy.Action {
+	case FatalActionError:
+		lb.write(entry, logrus.ErrorLevel, msg)
+	case FatalActionPanic:
+		lb.writeFatal(entry, level, msg)
+		panic(&FatalError{
+			Module:  moduleLogger.GetModuleName(),
+			Level:   level,
+			Message: msg,
+			Fields:  entry.Data,
+		})
+	case FatalActionExit:
+		lb.writeFatal(entry, level, msg)
+		moduleLogger.GetRoot().Exit(policy.exitCode())
+	default:
+		// Panic entries panic with the *logrus.Entry, as logrus does
+		lb.write(entry, level, msg)
+		moduleLogger.GetRoot().Exit(policy.exitCode())
+	}
+}
+
+// writeFatal writes a Fatal or Panic entry, recovering from the panic logrus raises for Panic entries
+func (lb *loggerBase) writeFatal(entry *logrus.Entry, level logrus.Level, msg string) {
+	defer func() {
+		if r := recover(); r != nil && level != logrus.PanicLevel {
+			panic(r)
+		}
+	}()
+	lb.write(entry, level, msg)
+}
+
+// write sets time, level and message of the entry and emits it
+func (lb *loggerBase) write(entry *logrus.Entry, level logrus.Level, msg string) {
+	entry.Time = lb.GetModuleLogger().GetRoot().GetClock().Now()
+	entry.Level = level
+	entry.Message = msg
+	lb.emit(entry, statsOf(lb.GetModuleLogger()))
+}
+
+// emit fires the root's hooks and hands the entry to the underlying logrus.Logger,
+// counting it in the given module counters
+func (lb *loggerBase) emit(entry *logrus.Entry, stats *moduleStats) {
+	if !loggerLevelEnabled(lb.GetModuleLogger().GetRoot(), entry.Level) {
+		stats.incSuppressed(entry.Level)
+		return
+	}
+	lb.emitUnfiltered(entry, stats)
+}
+
+// emitUnfiltered emits the entry like emit, regardless of the level of the underlying logrus.Logger
+func (lb *loggerBase) emitUnfiltered(entry *logrus.Entry, stats *moduleStats) {
+	rootLogger := lb.GetModuleLogger().GetRoot()
+	stats.incEmitted(entry.Level)
+
+	if err := hooksOf(rootLogger).Fire(entry.Level, entry); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to fire hook: %v\n", err)
+	}
+
+	if lr, ok := rootLogger.(*loggerRoot); ok && !entry.Logger.IsLevelEnabled(entry.Level) {
+		// logrus would discard the entry
+		lr.writeUnfiltered(entry)
+		return
+	}
+	entry.Log(entry.Level, entry.Message)
+}
+
+// record keeps the entry in the module's flight recorder
+func (lb *loggerBase) record(entry *logrus.Entry, level logrus.Level, msg string) {
+	recorder := flightRecorderOf(lb.GetModuleLogger())
+	if recorder == nil {
+		statsOf(lb.GetModuleLogger()).incSuppressed(level)
+		return
+	}
+
+	entry.Time = lb.GetModuleLogger().GetRoot().GetClock().Now()
+	entry.Level = level
+	entry.Message = msg
+	recorder.record(entry, statsOf(lb.GetModuleLogger()))
+}
+
+// flush writes the entries kept by the module's flight recorder, preserving their time.
+// Entries below the level of the underlying logrus.Logger cannot be written and count as dropped,
+// unless the root logger was created by New.
+func (lb *loggerBase) flush() {
+	recorder := flightRecorderOf(lb.GetModuleLogger())
+	if recorder == nil {
+		return
+	}
+
+	rootLogger := lb.GetModuleLogger().GetRoot()
+	logger := rootLogger.GetLogger()
+	for _, recorded := range recorder.drain() {
+		if !loggerLevelEnabled(rootLogger, recorded.entry.Level) {
+			recorded.stats.incDropped(recorded.entry.Level)
+			continue
+		}
+		recorded.entry.Logger = logger
+		recorded.entry.Data[RecordedField] = true
+		lb.emit(recorded.entry, recorded.stats)
+	}
+}
+
+// sprintlnn formats args like fmt.Sprintln, without the trailing newline
+func sprintlnn(args ...interface{}) string {
+	msg := fmt.Sprintln(args...)
+	return msg[:len(msg)-1]
 }
 
 func (lb *loggerBase) Debugf(format string, args ...interface{}) {
 	if entry := lb.newEntry(logrus.DebugLevel); entry != nil {
-		entry.Debugf(format, args...)
+		lb.log(entry, logrus.DebugLevel, fmt.Sprintf(format, args...))
 	}
 }
 
 func (lb *loggerBase) Infof(format string, args ...interface{}) {
 	if entry := lb.newEntry(logrus.InfoLevel); entry != nil {
-		entry.Infof(format, args...)
+		lb.log(entry, logrus.InfoLevel, fmt.Sprintf(format, args...))
 	}
 }
 
 func (lb *loggerBase) Printf(format string, args ...interface{}) {
-	lb.Infof(format, args...)
+	if entry := lb.newEntry(logrus.InfoLevel); entry != nil {
+		lb.log(entry, logrus.InfoLevel, fmt.Sprintf(format, args...))
+	}
 }
 
 func (lb *loggerBase) Warnf(format string, args ...interface{}) {
 	if entry := lb.newEntry(logrus.WarnLevel); entry != nil {
-		entry.Warnf(format, args...)
+		lb.log(entry, logrus.WarnLevel, fmt.Sprintf(format, args...))
 	}
 }
 
 func (lb *loggerBase) Warningf(format string, args ...interface{}) {
-	lb.Warnf(format, args...)
+	if entry := lb.newEntry(logrus.WarnLevel); entry != nil {
+		lb.log(entry, logrus.WarnLevel, fmt.Sprintf(format, args...))
+	}
 }
 
 func (lb *loggerBase) Errorf(format string, args ...interface{}) {
 	if entry := lb.newEntry(logrus.ErrorLevel); entry != nil {
-		entry.Errorf(format, args...)
+		lb.log(entry, logrus.ErrorLevel, fmt.Sprintf(format, args...))
 	}
 }
 
 func (lb *loggerBase) Fatalf(format string, args ...interface{}) {
 	if entry := lb.newEntry(logrus.FatalLevel); entry != nil {
-		entry.Fatalf(format, args...)
+		lb.log(entry, logrus.FatalLevel, fmt.Sprintf(format, args...))
 	}
 }
 
 func (lb *loggerBase) Panicf(format string, args ...interface{}) {
 	if entry := lb.newEntry(logrus.PanicLevel); entry != nil {
-		entry.Panicf(format, args...)
+		lb.log(entry, logrus.PanicLevel, fmt.Sprintf(format, args...))
 	}
 }
 
 func (lb *loggerBase) Debug(args ...interface{}) {
 	if entry := lb.newEntry(logrus.DebugLevel); entry != nil {
-		entry.Debug(args...)
+		lb.log(entry, logrus.DebugLevel, fmt.Sprint(args...))
 	}
 }
 
 func (lb *loggerBase) Info(args ...interface{}) {
 	if entry := lb.newEntry(logrus.InfoLevel); entry != nil {
-		entry.Info(args...)
+		lb.log(entry, logrus.InfoLevel, fmt.Sprint(args...))
 	}
 }
 
 func (lb *loggerBase) Print(args ...interface{}) {
-	lb.Info(args...)
+	if entry := lb.newEntry(logrus.InfoLevel); entry != nil {
+		lb.log(entry, logrus.InfoLevel, fmt.Sprint(args...))
+	}
 }
 
 func (lb *loggerBase) Warn(args ...interface{}) {
 	if entry := lb.newEntry(logrus.WarnLevel); entry != nil {
-		entry.Warn(args...)
+		lb.log(entry, logrus.WarnLevel, fmt.Sprint(args...))
 	}
 }
 
 func (lb *loggerBase) Warning(args ...interface{}) {
-	lb.Warn(args...)
+	if entry := lb.newEntry(logrus.WarnLevel); entry != nil {
+		lb.log(entry, logrus.WarnLevel, fmt.Sprint(args...))
+	}
 }
 
 func (lb *loggerBase) Error(args ...interface{}) {
 	if entry := lb.newEntry(logrus.ErrorLevel); entry != nil {
-		entry.Error(args...)
+		lb.log(entry, logrus.ErrorLevel, fmt.Sprint(args...))
 	}
 }
 
 func (lb *loggerBase) Fatal(args ...interface{}) {
 	if entry := lb.newEntry(logrus.FatalLevel); entry != nil {
-		entry.Fatal(args...)
+		lb.log(entry, logrus.FatalLevel, fmt.Sprint(args...))
 	}
 }
 
 func (lb *loggerBase) Panic(args ...interface{}) {
 	if entry := lb.newEntry(logrus.PanicLevel); entry != nil {
-		entry.Panic(args...)
+		lb.log(entry, logrus.PanicLevel, fmt.Sprint(args...))
 	}
 }
 
 func (lb *loggerBase) Debugln(args ...interface{}) {
 	if entry := lb.newEntry(logrus.DebugLevel); entry != nil {
-		entry.Debugln(args...)
+		lb.log(entry, logrus.DebugLevel, sprintlnn(args...))
 	}
 }
 
 func (lb *loggerBase) Infoln(args ...interface{}) {
 	if entry := lb.newEntry(logrus.InfoLevel); entry != nil {
-		entry.Infoln(args...)
+		lb.log(entry, logrus.InfoLevel, sprintlnn(args...))
 	}
 }
 
 func (lb *loggerBase) Println(args ...interface{}) {
-	lb.Infoln(args...)
+	if entry := lb.newEntry(logrus.InfoLevel); entry != nil {
+		lb.log(entry, logrus.InfoLevel, sprintlnn(args...))
+	}
 }
 
 func (lb *loggerBase) Warnln(args ...interface{}) {
 	if entry := lb.newEntry(logrus.WarnLevel); entry != nil {
-		entry.Warnln(args...)
+		lb.log(entry, logrus.WarnLevel, sprintlnn(args...))
 	}
 }
 
 func (lb *loggerBase) Warningln(args ...interface{}) {
-	lb.Warnln(args...)
+	if entry := lb.newEntry(logrus.WarnLevel); entry != nil {
+		lb.log(entry, logrus.WarnLevel, sprintlnn(args...))
+	}
 }
 
 func (lb *loggerBase) Errorln(args ...interface{}) {
 	if entry := lb.newEntry(logrus.ErrorLevel); entry != nil {
-		entry.Errorln(args...)
+		lb.log(entry, logrus.ErrorLevel, sprintlnn(args...))
 	}
 }
 
 func (lb *loggerBase) Fatalln(args ...interface{}) {
 	if entry := lb.newEntry(logrus.FatalLevel); entry != nil {
-		entry.Fatalln(args...)
+		lb.log(entry, logrus.FatalLevel, sprintlnn(args...))
 	}
 }
 
 func (lb *loggerBase) Panicln(args ...interface{}) {
 	if entry := lb.newEntry(logrus.PanicLevel); entry != nil {
-		entry.Panicln(args...)
+		lb.log(entry, logrus.PanicLevel, sprintlnn(args...))
 	}
 }
 
@@ -1,6 +1,9 @@
 package modular
 
 import (
+	"fmt"
+	"reflect"
+	"runtime"
 	"testing"
 
 	"errors"
@@ -404,3 +407,150 @@ func TestLoggerBase_Panicln(t *testing.T) {
 		lb.Panicln("test")
 	})
 }
+
+func TestLoggerBase_ReportCaller(t *testing.T) {
+	buffer := bytes.NewBufferString("")
+	logger := &logrus.Logger{
+		Out:       buffer,
+		Formatter: &logrus.JSONFormatter{},
+		Level:     logrus.DebugLevel,
+		ExitFunc:  func(int) {},
+	}
+	rl := NewRootLogger(logger)
+	module := rl.GetOrCreateChild("test", logrus.DebugLevel)
+	module.SetReportCaller(true)
+
+	logFns := []func(Logger){
+		func(l Logger) { l.Debug("test") },
+		func(l Logger) { l.Debugf("test") },
+		func(l Logger) { l.Debugln("test") },
+		func(l Logger) { l.Info("test") },
+		func(l Logger) { l.Infof("test") },
+		func(l Logger) { l.Infoln("test") },
+		func(l Logger) { l.Print("test") },
+		func(l Logger) { l.Printf("test") },
+		func(l Logger) { l.Println("test") },
+		func(l Logger) { l.Warn("test") },
+		func(l Logger) { l.Warnf("test") },
+		func(l Logger) { l.Warnln("test") },
+		func(l Logger) { l.Warning("test") },
+		func(l Logger) { l.Warningf("test") },
+		func(l Logger) { l.Warningln("test") },
+		func(l Logger) { l.Error("test") },
+		func(l Logger) { l.Errorf("test") },
+		func(l Logger) { l.Errorln("test") },
+		func(l Logger) { l.Fatal("test") },
+		func(l Logger) { l.Fatalf("test") },
+		func(l Logger) { l.Fatalln("test") },
+		func(l Logger) { defer func() { recover() }(); l.Panic("test") },
+		func(l Logger) { defer func() { recover() }(); l.Panicf("test") },
+		func(l Logger) { defer func() { recover() }(); l.Panicln("test") },
+	}
+
+	for _, logger := range []Logger{module, module.WithField("test", "test")} {
+		for _, logFn := range logFns {
+			fn := runtime.FuncForPC(reflect.ValueOf(logFn).Pointer())
+			file, line := fn.FileLine(fn.Entry())
+
+			buffer.Reset()
+			logFn(logger)
+
+			var data map[string]interface{}
+			require.NoError(t, json.Unmarshal(buffer.Bytes(), &data))
+			require.EqualValues(t, unescapeFuncName(fn.Name()), data[CallerFuncField])
+			require.NotContains(t, data[CallerFuncField], "%2e")
+			require.EqualValues(t, file, data[CallerFileField])
+			require.EqualValues(t, line, data[CallerLineField], "Wrong line for %s", fn.Name())
+		}
+	}
+
+	// Caller reporting is opt-in
+	module.SetReportCaller(false)
+	buffer.Reset()
+	module.Info("test")
+
+	var data map[string]interface{}
+	require.NoError(t, json.Unmarshal(buffer.Bytes(), &data))
+	require.NotContains(t, data, CallerFuncField)
+	require.NotContains(t, data, CallerFileField)
+	require.NotContains(t, data, CallerLineField)
+}
+
+func TestLoggerBase_FatalPolicy(t *testing.T) {
+	buffer := bytes.NewBufferString("")
+	var calls []string
+	logger := &logrus.Logger{
+		Out:       buffer,
+		Formatter: &logrus.JSONFormatter{},
+		Level:     logrus.DebugLevel,
+		ExitFunc: func(code int) {
+			calls = append(calls, fmt.Sprintf("exit %d", code))
+		},
+	}
+	rl := NewRootLogger(logger)
+	rl.RegisterShutdownHandler(func() {
+		calls = append(calls, "shutdown")
+	})
+	module := rl.GetOrCreateChild("test", logrus.DebugLevel)
+
+	lastLevel := func() string {
+		var data map[string]interface{}
+		require.NoError(t, json.Unmarshal(buffer.Bytes(), &data))
+		buffer.Reset()
+		return data["level"].(string)
+	}
+
+	// Default: Fatal exits with code 1 after running shutdown handlers
+	module.Fatal("test")
+	require.EqualValues(t, []string{"shutdown", "exit 1"}, calls)
+	require.EqualValues(t, "fatal", lastLevel())
+
+	// Default: Panic panics with the logrus entry
+	calls = nil
+	require.Panics(t, func() {
+		module.Panic("test")
+	})
+	require.Empty(t, calls)
+	require.EqualValues(t, "panic", lastLevel())
+
+	// Exit with configured code, for Panic as well
+	rl.SetFatalPolicy(FatalPolicy{Action: FatalActionExit, ExitCode: 3})
+	module.Fatalf("test")
+	require.NotPanics(t, func() {
+		module.Panicln("test")
+	})
+	require.EqualValues(t, []string{"shutdown", "exit 3", "shutdown", "exit 3"}, calls)
+
+	// Downgrade to Error
+	calls = nil
+	buffer.Reset()
+	module.SetFatalPolicy(FatalPolicy{Action: FatalActionError})
+	module.Fatal("test")
+	require.EqualValues(t, "error", lastLevel())
+	module.Panic("test")
+	require.EqualValues(t, "error", lastLevel())
+	require.Empty(t, calls)
+
+	// Panic with *FatalError
+	module.SetFatalPolicy(FatalPolicy{Action: FatalActionPanic})
+	for _, level := range []logrus.Level{logrus.FatalLevel, logrus.PanicLevel} {
+		func() {
+			defer func() {
+				fatalErr, ok := recover().(*FatalError)
+				require.True(t, ok)
+				require.EqualValues(t, "test", fatalErr.Module)
+				require.EqualValues(t, level, fatalErr.Level)
+				require.EqualValues(t, "test: 1", fatalErr.Message)
+				require.EqualValues(t, "value", fatalErr.Fields["key"])
+			}()
+			logger := module.WithField("key", "value")
+			if level == logrus.FatalLevel {
+				logger.Fatalf("test: %d", 1)
+			} else {
+				logger.Panicf("test: %d", 1)
+			}
+		}()
+		require.EqualValues(t, level.String(), lastLevel())
+	}
+	require.Empty(t, calls)
+}
@@ -1,8 +1,10 @@
 package modular
 
 import (
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/sirupsen/logrus"
 )
@@ -16,7 +18,27 @@ type loggerModule struct {
 	levelMutex sync.Mutex
 	level      logrus.Level
 
-	root RootLogger
+	root   RootLogger
+	parent *loggerModule
+
+	stats moduleStats
+
+	overridesMutex sync.Mutex
+	overrides      []*levelOverride
+
+	resolved atomic.Value
+
+	recorderMutex sync.Mutex
+	recorder      *flightRecorder
+
+	settingsMutex    sync.Mutex
+	reportCaller     *bool
+	fatalPolicy      *FatalPolicy
+	recorderSize     int
+	redactionRules   []RedactionRule
+	revealSecrets    *bool
+	errorExpansion   *ErrorExpansion
+	stackTracePolicy *StackTracePolicy
 
 	childrenMutex sync.Mutex
 	children      map[string]*loggerModule
@@ -26,45 +48,183 @@ func (lm *loggerModule) GetModuleName() string {
 	return lm.name
 }
 
+func (lm *loggerModule) GetModulePath() []string {
+	if lm.name == "" {
+		return []string{}
+	}
+	return strings.Split(lm.name, lm.separator())
+}
+
+// separator returns the module separator of the associated root
+func (lm *loggerModule) separator() string {
+	if lm.root == nil {
+		return DefaultSeparator
+	}
+	return lm.root.GetSeparator()
+}
+
 func (lm *loggerModule) SetLevel(level logrus.Level) {
+	var events []Event
+	lm.setLevel(level, Event{Source: EventSourceSetLevel}, &events)
+	lm.publish(events)
+}
+
+// setLevel sets the module's level and recursively propagates this change to all children,
+// collecting the resulting events based on change. Returns the previous level.
+func (lm *loggerModule) setLevel(level logrus.Level, change Event, events *[]Event) logrus.Level {
+	// Propagated levels do not override the level of a matching level rule
+	ownLevel, ownChange := level, change
+	if change.Source == EventSourceParent && lm.root != nil {
+		if rule, ok := lm.root.MatchLevelRule(lm.name); ok {
+			ownLevel = rule.Level
+			ownChange.Source = EventSourceLevelRule
+		}
+	}
+
 	lm.levelMutex.Lock()
 	defer lm.levelMutex.Unlock()
-	lm.level = level
+	oldLevel := lm.level
+	lm.level = ownLevel
+	if oldLevel != ownLevel {
+		event := ownChange
+		event.Type = EventLevelChanged
+		event.Module = lm.name
+		event.OldLevel = oldLevel
+		event.NewLevel = ownLevel
+		*events = append(*events, event)
+	}
 
 	// Propagate change to children
+	change.Source = EventSourceParent
 	lm.childrenMutex.Lock()
 	defer lm.childrenMutex.Unlock()
 	for _, child := range lm.children {
-		child.SetLevel(level)
+		child.setLevel(level, change, events)
 	}
+	return oldLevel
+}
+
+// setOwnLevel sets the module's level without propagating the change to children and returns the previous level
+func (lm *loggerModule) setOwnLevel(level logrus.Level) logrus.Level {
+	lm.levelMutex.Lock()
+	defer lm.levelMutex.Unlock()
+	oldLevel := lm.level
+	lm.level = level
+	return oldLevel
+}
+
+// getOwnLevel returns the module's level, ignoring level overrides
+func (lm *loggerModule) getOwnLevel() logrus.Level {
+	lm.levelMutex.Lock()
+	defer lm.levelMutex.Unlock()
+	return lm.level
 }
 
 func (lm *loggerModule) GetLevel() logrus.Level {
+	if level, ok := lm.overriddenLevel(); ok {
+		return level
+	}
+
 	lm.levelMutex.Lock()
 	defer lm.levelMutex.Unlock()
 	return lm.level
 }
 
+func (lm *loggerModule) SetReportCaller(enabled bool) {
+	lm.settingsMutex.Lock()
+	defer lm.settingsMutex.Unlock()
+	lm.reportCaller = &enabled
+	invalidateSettings()
+}
+
+func (lm *loggerModule) GetReportCaller() bool {
+	return lm.resolveSettings().reportCaller
+}
+
+func (lm *loggerModule) SetFatalPolicy(policy FatalPolicy) {
+	lm.settingsMutex.Lock()
+	defer lm.settingsMutex.Unlock()
+	lm.fatalPolicy = &policy
+	invalidateSettings()
+}
+
+func (lm *loggerModule) GetFatalPolicy() FatalPolicy {
+	return lm.resolveSettings().fatalPolicy
+}
+
 func (lm *loggerModule) GetRoot() RootLogger {
 	return lm.root
 }
 
 func (lm *loggerModule) getLocalChildNames(moduleName string) (localName, childName string) {
+	separator := lm.separator()
 	if lm.name != "" {
-		moduleName = strings.TrimPrefix(moduleName, lm.name+".")
+		moduleName = strings.TrimPrefix(moduleName, lm.name+separator)
 	}
 
 	localName = moduleName
 	childName = ""
-	if strings.Contains(moduleName, ".") {
-		moduleNameParts := strings.Split(moduleName, ".")
+	if strings.Contains(moduleName, separator) {
+		moduleNameParts := strings.Split(moduleName, separator)
 		localName = moduleNameParts[0]
-		childName = strings.Join(moduleNameParts[1:], ".")
+		childName = strings.Join(moduleNameParts[1:], separator)
 	}
 
 	return
 }
 
+// fullModuleName validates the given module name and prefixes it with the module's own name, if necessary
+func (lm *loggerModule) fullModuleName(moduleName string) (string, error) {
+	separator := lm.separator()
+	if _, err := splitModuleName(moduleName, separator); err != nil {
+		return "", err
+	}
+
+	if lm.name != "" && !strings.HasPrefix(moduleName, lm.name+separator) {
+		moduleName = strings.Join([]string{lm.name, moduleName}, separator)
+	}
+
+	// Check length and depth of the full name
+	if _, err := splitModuleName(moduleName, separator); err != nil {
+		return "", err
+	}
+
+	return moduleName, nil
+}
+
+// walk calls fn for the module and all of its descendants
+func (lm *loggerModule) walk(fn func(lm *loggerModule)) {
+	fn(lm)
+
+	lm.childrenMutex.Lock()
+	children := make([]*loggerModule, 0, len(lm.children))
+	for _, child := range lm.children {
+		children = append(children, child)
+	}
+	lm.childrenMutex.Unlock()
+
+	for _, child := range children {
+		child.walk(fn)
+	}
+}
+
+func (lm *loggerModule) GetChildren() []ModuleLogger {
+	lm.childrenMutex.Lock()
+	defer lm.childrenMutex.Unlock()
+
+	names := make([]string, 0, len(lm.children))
+	for name := range lm.children {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	children := make([]ModuleLogger, len(names))
+	for i, name := range names {
+		children[i] = lm.children[name]
+	}
+	return children
+}
+
 func (lm *loggerModule) GetChild(moduleName string) (ModuleLogger, error) {
 	lm.childrenMutex.Lock()
 	defer lm.childrenMutex.Unlock()
@@ -73,8 +233,9 @@ func (lm *loggerModule) GetChild(moduleName string) (ModuleLogger, error) {
 }
 
 func (lm *loggerModule) getChild(moduleName string) (ModuleLogger, error) {
-	if lm.name != "" && !strings.HasPrefix(moduleName, lm.name+".") {
-		moduleName = strings.Join([]string{lm.name, moduleName}, ".")
+	moduleName, err := lm.fullModuleName(moduleName)
+	if err != nil {
+		return nil, err
 	}
 
 	localModuleName, childModuleName := lm.getLocalChildNames(moduleName)
@@ -82,7 +243,7 @@ func (lm *loggerModule) getChild(moduleName string) (ModuleLogger, error) {
 	childModule, ok := lm.children[localModuleName]
 
 	if !ok {
-		return nil, ErrChildNotFound
+		return nil, &ChildNotFoundError{Module: moduleName}
 	}
 
 	if childModuleName == "" {
@@ -93,28 +254,38 @@ func (lm *loggerModule) getChild(moduleName string) (ModuleLogger, error) {
 }
 
 func (lm *loggerModule) CreateChild(moduleName string, defaultLevel logrus.Level) (ModuleLogger, error) {
+	var created []*loggerModule
 	lm.childrenMutex.Lock()
-	defer lm.childrenMutex.Unlock()
-	return lm.createChild(moduleName, defaultLevel)
+	child, err := lm.createChild(moduleName, defaultLevel, &created)
+	lm.childrenMutex.Unlock()
+
+	lm.publish(createdEvents(created))
+	return child, err
 }
 
-func (lm *loggerModule) createChild(moduleName string, defaultLevel logrus.Level) (ModuleLogger, error) {
-	if lm.name != "" && !strings.HasPrefix(moduleName, lm.name+".") {
-		moduleName = strings.Join([]string{lm.name, moduleName}, ".")
+// createChild creates the child with the given name, collecting all created modules.
+// The caller must hold the module's childrenMutex.
+func (lm *loggerModule) createChild(moduleName string, defaultLevel logrus.Level, created *[]*loggerModule) (ModuleLogger, error) {
+	moduleName, err := lm.fullModuleName(moduleName)
+	if err != nil {
+		return nil, err
 	}
 
+	separator := lm.separator()
 	localModuleName, childModuleName := lm.getLocalChildNames(moduleName)
 	fullLocalModuleName := localModuleName
 	if lm.name != "" {
-		fullLocalModuleName = strings.Join([]string{lm.name, localModuleName}, ".")
+		fullLocalModuleName = strings.Join([]string{lm.name, localModuleName}, separator)
 	}
 
 	childModule, ok := lm.children[localModuleName]
 
 	if ok && childModuleName == "" {
-		return nil, ErrChildExists
+		return nil, &ChildExistsError{Module: moduleName}
 	} else if ok {
-		return childModule.CreateChild(moduleName, defaultLevel)
+		childModule.childrenMutex.Lock()
+		defer childModule.childrenMutex.Unlock()
+		return childModule.createChild(moduleName, defaultLevel, created)
 	}
 
 	// Child does not exist, create it.
@@ -124,21 +295,40 @@ func (lm *loggerModule) createChild(moduleName string, defaultLevel logrus.Level
 		},
 		name:     fullLocalModuleName,
 		root:     lm.root,
+		parent:   lm,
 		level:    defaultLevel,
 		children: make(map[string]*loggerModule, 1),
 	}
 	child.moduleLogger = child
 
+	// Level rules take precedence over the default level
+	if lm.root != nil {
+		if rule, ok := lm.root.MatchLevelRule(fullLocalModuleName); ok {
+			child.level = rule.Level
+		}
+	}
+
 	lm.children[localModuleName] = child
+	*created = append(*created, child)
 
 	if childModuleName == "" {
 		return child, nil
 	}
 
-	return child.CreateChild(moduleName, defaultLevel)
+	child.childrenMutex.Lock()
+	defer child.childrenMutex.Unlock()
+	return child.createChild(moduleName, defaultLevel, created)
 }
 
 func (lm *loggerModule) GetOrCreateChild(moduleName string, defaultLevel logrus.Level) ModuleLogger {
+	var created []*loggerModule
+	child := lm.getOrCreateChild(moduleName, defaultLevel, &created)
+
+	lm.publish(createdEvents(created))
+	return child
+}
+
+func (lm *loggerModule) getOrCreateChild(moduleName string, defaultLevel logrus.Level, created *[]*loggerModule) ModuleLogger {
 	lm.childrenMutex.Lock()
 	defer lm.childrenMutex.Unlock()
 
@@ -146,10 +336,57 @@ func (lm *loggerModule) GetOrCreateChild(moduleName string, defaultLevel logrus.
 		return child
 	}
 
-	if child, err := lm.createChild(moduleName, defaultLevel); err == nil {
+	if child, err := lm.createChild(moduleName, defaultLevel, created); err == nil {
 		return child
 	}
 
-	// should be unreachable
+	// only reachable for invalid module names
+	return nil
+}
+
+func (lm *loggerModule) RemoveChild(moduleName string) error {
+	var removed []*loggerModule
+	lm.childrenMutex.Lock()
+	err := lm.removeChild(moduleName, &removed)
+	lm.childrenMutex.Unlock()
+
+	events := make([]Event, len(removed))
+	for i, module := range removed {
+		module.clearLevelOverrides()
+		events[i] = Event{
+			Type:     EventModuleRemoved,
+			Module:   module.name,
+			OldLevel: module.getOwnLevel(),
+			Source:   EventSourceRemoveChild,
+		}
+	}
+	lm.publish(events)
+	return err
+}
+
+// removeChild removes the child with the given name, collecting the child and all of its descendants.
+// The caller must hold the module's childrenMutex.
+func (lm *loggerModule) removeChild(moduleName string, removed *[]*loggerModule) error {
+	moduleName, err := lm.fullModuleName(moduleName)
+	if err != nil {
+		return err
+	}
+
+	localModuleName, childModuleName := lm.getLocalChildNames(moduleName)
+	childModule, ok := lm.children[localModuleName]
+	if !ok {
+		return &ChildNotFoundError{Module: moduleName}
+	}
+
+	if childModuleName != "" {
+		childModule.childrenMutex.Lock()
+		defer childModule.childrenMutex.Unlock()
+		return childModule.removeChild(moduleName, removed)
+	}
+
+	delete(lm.children, localModuleName)
+	childModule.walk(func(module *loggerModule) {
+		*removed = append(*removed, module)
+	})
 	return nil
 }
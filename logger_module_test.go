@@ -1,6 +1,8 @@
 package modular
 
 import (
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/sirupsen/logrus"
@@ -15,6 +17,16 @@ func TestLoggerModule_GetModuleName(t *testing.T) {
 	require.EqualValues(t, "test", lm.GetModuleName())
 }
 
+func TestLoggerModule_GetModulePath(t *testing.T) {
+	lm := &loggerModule{
+		name: "test.module",
+	}
+	require.EqualValues(t, []string{"test", "module"}, lm.GetModulePath())
+
+	lm = &loggerModule{}
+	require.EqualValues(t, []string{}, lm.GetModulePath())
+}
+
 func TestLoggerModule_GetRoot(t *testing.T) {
 	lm := &loggerModule{
 		root: &loggerRoot{},
@@ -61,7 +73,8 @@ func TestLoggerModule_CreateChild(t *testing.T) {
 
 	child, err = lm.CreateChild("test", logrus.FatalLevel)
 	require.Nil(t, child)
-	require.EqualError(t, err, ErrChildExists.Error())
+	require.True(t, errors.Is(err, ErrChildExists))
+	require.EqualError(t, err, "Child logger exists: test.module.test")
 
 	// Test "local" creation with full name
 	child, err = lm.CreateChild("test.module.test2", logrus.FatalLevel)
@@ -72,11 +85,13 @@ func TestLoggerModule_CreateChild(t *testing.T) {
 
 	child, err = lm.CreateChild("test.module.test2", logrus.FatalLevel)
 	require.Nil(t, child)
-	require.EqualError(t, err, ErrChildExists.Error())
+	require.True(t, errors.Is(err, ErrChildExists))
+	require.EqualError(t, err, "Child logger exists: test.module.test2")
 
 	child, err = lm.CreateChild("test2", logrus.FatalLevel)
 	require.Nil(t, child)
-	require.EqualError(t, err, ErrChildExists.Error())
+	require.True(t, errors.Is(err, ErrChildExists))
+	require.EqualError(t, err, "Child logger exists: test.module.test2")
 
 	// Test nested creation
 	child, err = lm.CreateChild("test3.test4", logrus.FatalLevel)
@@ -102,7 +117,8 @@ func TestLoggerModule_GetChild(t *testing.T) {
 	// Test: non-existent child
 	child, err := lm.GetChild("test.module.nest")
 	require.Nil(t, child)
-	require.EqualError(t, err, ErrChildNotFound.Error())
+	require.True(t, errors.Is(err, ErrChildNotFound))
+	require.EqualError(t, err, "Child logger not found: test.module.nest")
 
 	// Create nested children
 	child, err = lm.CreateChild("test.module.nest.nest2", logrus.FatalLevel)
@@ -126,6 +142,35 @@ func TestLoggerModule_GetChild(t *testing.T) {
 	require.NotNil(t, "test.module.nest.nest2", child)
 }
 
+func TestLoggerModule_CreateChild_InvalidName(t *testing.T) {
+	lm := &loggerModule{
+		name:     "test.module",
+		children: make(map[string]*loggerModule),
+	}
+
+	for _, name := range []string{"", ".", ".test", "test.", "te..st", "te st", "täst!"} {
+		child, err := lm.CreateChild(name, logrus.FatalLevel)
+		require.Nil(t, child)
+		require.True(t, errors.Is(err, ErrInvalidModuleName), "Module name %q", name)
+
+		child, err = lm.GetChild(name)
+		require.Nil(t, child)
+		require.True(t, errors.Is(err, ErrInvalidModuleName), "Module name %q", name)
+
+		require.Nil(t, lm.GetOrCreateChild(name, logrus.FatalLevel))
+	}
+	require.Empty(t, lm.children)
+
+	// Depth is checked against the full module name
+	child, err := lm.CreateChild(strings.Repeat("a.", MaxModuleDepth-2)+"a", logrus.FatalLevel)
+	require.Nil(t, child)
+	require.True(t, errors.Is(err, ErrModuleNameTooDeep))
+
+	child, err = lm.CreateChild(strings.Repeat("a.", MaxModuleDepth-3)+"a", logrus.FatalLevel)
+	require.NoError(t, err)
+	require.Len(t, child.GetModulePath(), MaxModuleDepth)
+}
+
 func TestLoggerModule_GetOrCreateChild(t *testing.T) {
 	lm := &loggerModule{
 		name:     "test.module",
@@ -171,3 +216,80 @@ func TestLoggerModule_SetLevel(t *testing.T) {
 	require.EqualValues(t, logrus.DebugLevel, lm.GetLevel())
 	require.EqualValues(t, logrus.DebugLevel, child.GetLevel())
 }
+
+func TestLoggerModule_SetReportCaller(t *testing.T) {
+	lm := &loggerModule{
+		name:     "test",
+		children: make(map[string]*loggerModule),
+	}
+	require.False(t, lm.GetReportCaller())
+
+	child := lm.GetOrCreateChild("nest", logrus.InfoLevel)
+	nested := child.GetOrCreateChild("nest2", logrus.InfoLevel)
+
+	// Children inherit the setting
+	lm.SetReportCaller(true)
+	require.True(t, lm.GetReportCaller())
+	require.True(t, child.GetReportCaller())
+	require.True(t, nested.GetReportCaller())
+
+	// ... unless they configure it themselves
+	child.SetReportCaller(false)
+	require.True(t, lm.GetReportCaller())
+	require.False(t, child.GetReportCaller())
+	require.False(t, nested.GetReportCaller())
+}
+
+func TestLoggerModule_SetFatalPolicy(t *testing.T) {
+	lm := &loggerModule{
+		name:     "test",
+		children: make(map[string]*loggerModule),
+	}
+	require.EqualValues(t, FatalPolicy{}, lm.GetFatalPolicy())
+
+	child := lm.GetOrCreateChild("nest", logrus.InfoLevel)
+	nested := child.GetOrCreateChild("nest2", logrus.InfoLevel)
+
+	lm.SetFatalPolicy(FatalPolicy{Action: FatalActionError})
+	require.EqualValues(t, FatalPolicy{Action: FatalActionError}, child.GetFatalPolicy())
+	require.EqualValues(t, FatalPolicy{Action: FatalActionError}, nested.GetFatalPolicy())
+
+	child.SetFatalPolicy(FatalPolicy{Action: FatalActionExit, ExitCode: 2})
+	require.EqualValues(t, FatalPolicy{Action: FatalActionError}, lm.GetFatalPolicy())
+	require.EqualValues(t, FatalPolicy{Action: FatalActionExit, ExitCode: 2}, nested.GetFatalPolicy())
+}
+
+func TestLoggerModule_GetChildren(t *testing.T) {
+	lm := &loggerModule{
+		name:     "test",
+		children: make(map[string]*loggerModule),
+	}
+	require.Empty(t, lm.GetChildren())
+
+	b := lm.GetOrCreateChild("b", logrus.InfoLevel)
+	a := lm.GetOrCreateChild("a", logrus.InfoLevel)
+	lm.GetOrCreateChild("a.nested", logrus.InfoLevel)
+
+	require.EqualValues(t, []ModuleLogger{a, b}, lm.GetChildren())
+}
+
+func TestLoggerModule_RemoveChild(t *testing.T) {
+	lm := &loggerModule{
+		name:     "test",
+		children: make(map[string]*loggerModule),
+	}
+	lm.GetOrCreateChild("a.nested", logrus.InfoLevel)
+	lm.GetOrCreateChild("b", logrus.InfoLevel)
+
+	require.NoError(t, lm.RemoveChild("a.nested"))
+	_, err := lm.GetChild("a.nested")
+	require.EqualValues(t, &ChildNotFoundError{Module: "test.a.nested"}, err)
+	_, err = lm.GetChild("a")
+	require.NoError(t, err)
+
+	require.NoError(t, lm.RemoveChild("test.a"))
+	require.Len(t, lm.GetChildren(), 1)
+
+	require.EqualValues(t, &ChildNotFoundError{Module: "test.a"}, lm.RemoveChild("a"))
+	require.True(t, errors.Is(lm.RemoveChild("a..b"), ErrInvalidModuleName))
+}
@@ -1,6 +1,10 @@
 package modular
 
 import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/sirupsen/logrus"
@@ -9,19 +13,96 @@ import (
 // DefaultModuleField defines the default field to use for the module name
 const DefaultModuleField = "module"
 
+// ModuleFieldStyle defines how the module name is represented in log entries
+type ModuleFieldStyle int
+
+const (
+	// ModuleFieldStyleName represents the module by its full name, joined by the module field separator
+	ModuleFieldStyleName ModuleFieldStyle = iota
+	// ModuleFieldStylePath represents the module as an array of path segments, i.e. ["db", "query"]
+	ModuleFieldStylePath
+	// ModuleFieldStyleDepth represents the module as one field per depth, i.e. module.0, module.1
+	ModuleFieldStyleDepth
+	// ModuleFieldStyleLeaf represents the module by its leaf name only
+	ModuleFieldStyleLeaf
+)
+
 type loggerRoot struct {
 	loggerModule
 
 	logger *logrus.Logger
+	// gatesLevels is set for roots created by New, which ignore the level of the logger
+	gatesLevels bool
+	writeMutex  sync.Mutex
+
+	moduleFieldMutex     sync.Mutex
+	moduleField          string
+	moduleFieldStyle     ModuleFieldStyle
+	moduleFieldSeparator string
+
+	separatorMutex sync.Mutex
+	separator      string
+
+	packageRulesMutex sync.Mutex
+	packageRules      []PackageRule
+
+	shutdownHandlersMutex sync.Mutex
+	shutdownHandlers      []func()
 
-	moduleFieldMutex sync.Mutex
-	moduleField      string
+	hooksMutex sync.Mutex
+	hooks      logrus.LevelHooks
+
+	clockMutex sync.Mutex
+	clock      Clock
+
+	aliasesMutex sync.Mutex
+	aliases      map[string]string
+
+	levelRulesMutex sync.Mutex
+	levelRules      []LevelRule
+
+	hashSaltMutex sync.Mutex
+	hashSalt      []byte
+
+	subscribers subscribers
+	auditLog    auditLog
 }
 
 func (lr *loggerRoot) GetLogger() *logrus.Logger {
 	return lr.logger
 }
 
+// writeUnfiltered writes an entry below the level of the logger as logrus.Entry.Log would:
+// the logger's hooks are fired and the entry is formatted and written to the logger's output.
+// The logger's own lock is not accessible, such writes are serialized per root.
+func (lr *loggerRoot) writeUnfiltered(entry *logrus.Entry) {
+	logger := entry.Logger
+	if err := logger.Hooks.Fire(entry.Level, entry); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to fire hook: %v\n", err)
+	}
+
+	serialized, err := logger.Formatter.Format(entry)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to obtain reader, %v\n", err)
+		return
+	}
+
+	lr.writeMutex.Lock()
+	defer lr.writeMutex.Unlock()
+	if _, err := logger.Out.Write(serialized); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write to log, %v\n", err)
+	}
+}
+
+// loggerLevelEnabled reports whether the logger of the given root writes entries of the given level.
+// Roots created by New gate levels inside the module tree only.
+func loggerLevelEnabled(rootLogger RootLogger, level logrus.Level) bool {
+	if lr, ok := rootLogger.(*loggerRoot); ok && lr.gatesLevels {
+		return true
+	}
+	return rootLogger.GetLogger().IsLevelEnabled(level)
+}
+
 func (lr *loggerRoot) GetModuleField() string {
 	lr.moduleFieldMutex.Lock()
 	defer lr.moduleFieldMutex.Unlock()
@@ -38,3 +119,203 @@ func (lr *loggerRoot) SetModuleField(field string) {
 
 	lr.moduleField = field
 }
+
+func (lr *loggerRoot) GetModuleFieldStyle() ModuleFieldStyle {
+	lr.moduleFieldMutex.Lock()
+	defer lr.moduleFieldMutex.Unlock()
+	return lr.moduleFieldStyle
+}
+
+func (lr *loggerRoot) SetModuleFieldStyle(style ModuleFieldStyle) {
+	lr.moduleFieldMutex.Lock()
+	defer lr.moduleFieldMutex.Unlock()
+
+	lr.moduleFieldStyle = style
+}
+
+func (lr *loggerRoot) GetModuleFieldSeparator() string {
+	lr.moduleFieldMutex.Lock()
+	defer lr.moduleFieldMutex.Unlock()
+
+	if lr.moduleFieldSeparator == "" {
+		return lr.GetSeparator()
+	}
+	return lr.moduleFieldSeparator
+}
+
+func (lr *loggerRoot) SetModuleFieldSeparator(separator string) {
+	lr.moduleFieldMutex.Lock()
+	defer lr.moduleFieldMutex.Unlock()
+
+	lr.moduleFieldSeparator = separator
+}
+
+func (lr *loggerRoot) GetSeparator() string {
+	lr.separatorMutex.Lock()
+	defer lr.separatorMutex.Unlock()
+
+	if lr.separator == "" {
+		return DefaultSeparator
+	}
+	return lr.separator
+}
+
+func (lr *loggerRoot) SetSeparator(separator string) error {
+	if separator == "" {
+		return ErrInvalidSeparator
+	}
+	lr.childrenMutex.Lock()
+	defer lr.childrenMutex.Unlock()
+	if len(lr.children) > 0 {
+		return ErrSeparatorInUse
+	}
+
+	lr.separatorMutex.Lock()
+	defer lr.separatorMutex.Unlock()
+
+	lr.separator = separator
+	return nil
+}
+
+func (lr *loggerRoot) SetPackageRules(rules ...PackageRule) {
+	lr.packageRulesMutex.Lock()
+	defer lr.packageRulesMutex.Unlock()
+
+	lr.packageRules = rules
+}
+
+func (lr *loggerRoot) getPackageRules() []PackageRule {
+	lr.packageRulesMutex.Lock()
+	defer lr.packageRulesMutex.Unlock()
+
+	if lr.packageRules == nil {
+		return DefaultPackageRules
+	}
+	return lr.packageRules
+}
+
+func (lr *loggerRoot) ForPackage() ModuleLogger {
+	moduleName := packageModuleName(callerPackage(1), lr.getPackageRules(), lr.GetSeparator())
+	if moduleName == "" {
+		return lr
+	}
+
+	if module := lr.GetOrCreateChild(moduleName, lr.GetLevel()); module != nil {
+		return module
+	}
+	return lr
+}
+
+func (lr *loggerRoot) RegisterShutdownHandler(handler func()) {
+	lr.shutdownHandlersMutex.Lock()
+	defer lr.shutdownHandlersMutex.Unlock()
+
+	lr.shutdownHandlers = append(lr.shutdownHandlers, handler)
+}
+
+func (lr *loggerRoot) Exit(code int) {
+	lr.shutdownHandlersMutex.Lock()
+	handlers := make([]func(), len(lr.shutdownHandlers))
+	copy(handlers, lr.shutdownHandlers)
+	lr.shutdownHandlersMutex.Unlock()
+
+	for _, handler := range handlers {
+		runShutdownHandler(handler)
+	}
+
+	lr.logger.Exit(code)
+}
+
+// runShutdownHandler runs the given handler, ignoring panics so the remaining handlers run and exit happens
+func runShutdownHandler(handler func()) {
+	defer func() {
+		recover()
+	}()
+	handler()
+}
+
+func (lr *loggerRoot) AddHook(hook logrus.Hook) {
+	lr.hooksMutex.Lock()
+	defer lr.hooksMutex.Unlock()
+
+	// Hooks are copied on write, so getHooks can return them without copying
+	hooks := lr.copyHooks()
+	hooks.Add(hook)
+	lr.hooks = hooks
+}
+
+func (lr *loggerRoot) GetHooks() logrus.LevelHooks {
+	lr.hooksMutex.Lock()
+	defer lr.hooksMutex.Unlock()
+
+	return lr.copyHooks()
+}
+
+// copyHooks returns a copy of the hooks, hooksMutex must be held
+func (lr *loggerRoot) copyHooks() logrus.LevelHooks {
+	hooks := make(logrus.LevelHooks, len(lr.hooks))
+	for level, levelHooks := range lr.hooks {
+		hooks[level] = append([]logrus.Hook(nil), levelHooks...)
+	}
+	return hooks
+}
+
+// getHooks returns the hooks without copying them, they must not be modified
+func (lr *loggerRoot) getHooks() logrus.LevelHooks {
+	lr.hooksMutex.Lock()
+	defer lr.hooksMutex.Unlock()
+
+	return lr.hooks
+}
+
+// hooksOf returns the hooks of the given root, which must not be modified
+func hooksOf(rootLogger RootLogger) logrus.LevelHooks {
+	if lr, ok := rootLogger.(interface{ getHooks() logrus.LevelHooks }); ok {
+		return lr.getHooks()
+	}
+	return rootLogger.GetHooks()
+}
+
+func (lr *loggerRoot) GetClock() Clock {
+	lr.clockMutex.Lock()
+	defer lr.clockMutex.Unlock()
+
+	if lr.clock == nil {
+		return SystemClock
+	}
+	return lr.clock
+}
+
+func (lr *loggerRoot) SetClock(clock Clock) {
+	lr.clockMutex.Lock()
+	defer lr.clockMutex.Unlock()
+
+	lr.clock = clock
+}
+
+// addModuleFields adds the representation of the module's name to the given fields,
+// according to the root's module field, style and separator.
+func addModuleFields(fields logrus.Fields, rootLogger RootLogger, moduleLogger ModuleLogger) {
+	moduleFieldName := rootLogger.GetModuleField()
+
+	switch rootLogger.GetModuleFieldStyle() {
+	case ModuleFieldStylePath:
+		fields[moduleFieldName] = moduleLogger.GetModulePath()
+	case ModuleFieldStyleDepth:
+		for depth, name := range moduleLogger.GetModulePath() {
+			fields[moduleFieldName+"."+strconv.Itoa(depth)] = name
+		}
+	case ModuleFieldStyleLeaf:
+		leaf := ""
+		if path := moduleLogger.GetModulePath(); len(path) > 0 {
+			leaf = path[len(path)-1]
+		}
+		fields[moduleFieldName] = leaf
+	default:
+		if separator := rootLogger.GetModuleFieldSeparator(); separator != rootLogger.GetSeparator() {
+			fields[moduleFieldName] = strings.Join(moduleLogger.GetModulePath(), separator)
+		} else {
+			fields[moduleFieldName] = moduleLogger.GetModuleName()
+		}
+	}
+}
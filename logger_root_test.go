@@ -1,6 +1,8 @@
 package modular
 
 import (
+	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/sirupsen/logrus"
@@ -53,3 +55,153 @@ func TestLoggerRoot_GetOrCreateChild(t *testing.T) {
 	require.EqualValues(t, "test.nested", nestedModuleLogger.GetModuleName())
 
 }
+
+func TestLoggerRoot_SetModuleFieldStyle(t *testing.T) {
+	rl := &loggerRoot{}
+	require.EqualValues(t, ModuleFieldStyleName, rl.GetModuleFieldStyle())
+
+	rl.SetModuleFieldStyle(ModuleFieldStylePath)
+	require.EqualValues(t, ModuleFieldStylePath, rl.GetModuleFieldStyle())
+}
+
+func TestLoggerRoot_SetModuleFieldSeparator(t *testing.T) {
+	rl := &loggerRoot{}
+	require.EqualValues(t, DefaultSeparator, rl.GetModuleFieldSeparator())
+
+	rl.SetModuleFieldSeparator("::")
+	require.EqualValues(t, "::", rl.GetModuleFieldSeparator())
+
+	rl.SetModuleFieldSeparator("")
+	require.EqualValues(t, DefaultSeparator, rl.GetModuleFieldSeparator())
+
+	// Falls back to the module separator
+	rl.separator = "/"
+	require.EqualValues(t, "/", rl.GetModuleFieldSeparator())
+}
+
+func TestLoggerRoot_SetSeparator(t *testing.T) {
+	rl := NewRootLogger(logrus.New())
+	require.EqualValues(t, DefaultSeparator, rl.GetSeparator())
+
+	require.EqualError(t, rl.SetSeparator(""), ErrInvalidSeparator.Error())
+	require.EqualValues(t, DefaultSeparator, rl.GetSeparator())
+
+	require.NoError(t, rl.SetSeparator("/"))
+	require.EqualValues(t, "/", rl.GetSeparator())
+
+	// Import paths are not split at the dots of the domain
+	child, err := rl.CreateChild("github.com/acme/x", logrus.InfoLevel)
+	require.NoError(t, err)
+	require.EqualValues(t, "github.com/acme/x", child.GetModuleName())
+	require.EqualValues(t, []string{"github.com", "acme", "x"}, child.GetModulePath())
+
+	child, err = rl.GetChild("github.com/acme")
+	require.NoError(t, err)
+	require.EqualValues(t, "github.com/acme", child.GetModuleName())
+
+	nested := child.GetOrCreateChild("y/z", logrus.InfoLevel)
+	require.NotNil(t, nested)
+	require.EqualValues(t, "github.com/acme/y/z", nested.GetModuleName())
+
+	_, err = rl.GetChild("github.com/acme/y/z")
+	require.NoError(t, err)
+
+	// Empty segments are rejected
+	for _, name := range []string{"", "/", "a//b", "a/", "/a"} {
+		child, err = rl.CreateChild(name, logrus.InfoLevel)
+		require.Nil(t, child)
+		require.True(t, errors.Is(err, ErrEmptyModuleSegment))
+
+		child, err = rl.GetChild(name)
+		require.Nil(t, child)
+		require.True(t, errors.Is(err, ErrEmptyModuleSegment))
+
+		require.Nil(t, rl.GetOrCreateChild(name, logrus.InfoLevel))
+	}
+
+	// Separator cannot be changed once children exist
+	require.EqualError(t, rl.SetSeparator("::"), ErrSeparatorInUse.Error())
+	require.EqualValues(t, "/", rl.GetSeparator())
+}
+
+func TestAddModuleFields(t *testing.T) {
+	rl := NewRootLogger(logrus.New())
+	module := rl.GetOrCreateChild("db.query", logrus.DebugLevel)
+
+	fields := logrus.Fields{}
+	addModuleFields(fields, rl, module)
+	require.EqualValues(t, logrus.Fields{"module": "db.query"}, fields)
+
+	rl.SetModuleFieldSeparator("/")
+	fields = logrus.Fields{}
+	addModuleFields(fields, rl, module)
+	require.EqualValues(t, logrus.Fields{"module": "db/query"}, fields)
+
+	rl.SetModuleFieldStyle(ModuleFieldStylePath)
+	fields = logrus.Fields{}
+	addModuleFields(fields, rl, module)
+	require.EqualValues(t, logrus.Fields{"module": []string{"db", "query"}}, fields)
+
+	rl.SetModuleFieldStyle(ModuleFieldStyleDepth)
+	fields = logrus.Fields{}
+	addModuleFields(fields, rl, module)
+	require.EqualValues(t, logrus.Fields{"module.0": "db", "module.1": "query"}, fields)
+
+	rl.SetModuleFieldStyle(ModuleFieldStyleLeaf)
+	fields = logrus.Fields{}
+	addModuleFields(fields, rl, module)
+	require.EqualValues(t, logrus.Fields{"module": "query"}, fields)
+
+	// Root module has no path segments
+	fields = logrus.Fields{}
+	addModuleFields(fields, rl, rl)
+	require.EqualValues(t, logrus.Fields{"module": ""}, fields)
+}
+
+func TestLoggerRoot_Exit(t *testing.T) {
+	var calls []string
+	logger := logrus.New()
+	logger.ExitFunc = func(code int) {
+		calls = append(calls, fmt.Sprintf("exit %d", code))
+	}
+	rl := NewRootLogger(logger)
+	rl.RegisterShutdownHandler(func() {
+		calls = append(calls, "first")
+	})
+	rl.RegisterShutdownHandler(func() {
+		panic("ignored")
+	})
+	rl.RegisterShutdownHandler(func() {
+		calls = append(calls, "second")
+	})
+
+	rl.Exit(4)
+	require.EqualValues(t, []string{"first", "second", "exit 4"}, calls)
+}
+
+func TestLoggerRoot_AddHook(t *testing.T) {
+	rl := &loggerRoot{}
+	require.Empty(t, rl.GetHooks())
+
+	hook := &recordingHook{}
+	rl.AddHook(hook)
+	hooks := rl.GetHooks()
+	require.Len(t, hooks, len(logrus.AllLevels))
+	require.EqualValues(t, []logrus.Hook{hook}, hooks[logrus.InfoLevel])
+
+	// Modifying the copy does not affect the root
+	hooks.Add(&recordingHook{})
+	require.Len(t, rl.GetHooks()[logrus.InfoLevel], 1)
+}
+
+func TestLoggerRoot_SetClock(t *testing.T) {
+	rl := &loggerRoot{}
+	require.EqualValues(t, SystemClock, rl.GetClock())
+
+	clock := &fixedClock{}
+	rl.SetClock(clock)
+	require.EqualValues(t, clock, rl.GetClock())
+
+	rl.SetClock(nil)
+	require.EqualValues(t, SystemClock, rl.GetClock())
+}
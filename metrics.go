@@ -0,0 +1,70 @@
+package modular
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// MetricsContentType defines the content type of the Prometheus text format
+const MetricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// metricsLabelEscaper escapes label values as required by the Prometheus text format
+var metricsLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// NewMetricsHandler returns a http.Handler exposing the entry counters of all modules of the given root
+// in the Prometheus text format, i.e.
+//
+//	logrus_modular_entries_emitted_total{module="payments.card",level="error"} 3
+func NewMetricsHandler(root RootLogger) http.Handler {
+	return newMetricsHandler(func(fn func(name string, module ModuleLogger)) {
+		walkModules(root, func(module ModuleLogger) {
+			fn(module.GetModuleName(), module)
+		})
+	})
+}
+
+// MetricsHandler returns a http.Handler exposing the entry counters of all modules of all mounted trees
+// like NewMetricsHandler, using the prefixed module names, i.e. module="tenantA.payments.card"
+func (r *Registry) MetricsHandler() http.Handler {
+	return newMetricsHandler(r.Walk)
+}
+
+// newMetricsHandler returns a http.Handler exposing the entry counters of the modules passed by walk
+func newMetricsHandler(walk func(fn func(name string, module ModuleLogger))) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var names []string
+		var modules []ModuleLogger
+		walk(func(name string, module ModuleLogger) {
+			names = append(names, name)
+			modules = append(modules, module)
+		})
+		snapshots := make([]Stats, len(modules))
+		for i, module := range modules {
+			snapshots[i] = module.Stats()
+		}
+
+		w.Header().Set("Content-Type", MetricsContentType)
+		bw := bufio.NewWriter(w)
+		writeMetric := func(name, help string, counters func(stats Stats) map[logrus.Level]uint64) {
+			fmt.Fprintf(bw, "# HELP %s %s\n# TYPE %s counter\n", name, help, name)
+			for i, moduleName := range names {
+				values := counters(snapshots[i])
+				for _, level := range logrus.AllLevels {
+					fmt.Fprintf(bw, "%s{module=\"%s\",level=\"%s\"} %d\n",
+						name, metricsLabelEscaper.Replace(moduleName), level, values[level])
+				}
+			}
+		}
+		writeMetric("logrus_modular_entries_emitted_total", "Number of entries written, by module and level.",
+			func(stats Stats) map[logrus.Level]uint64 { return stats.Emitted })
+		writeMetric("logrus_modular_entries_suppressed_total", "Number of entries below the effective level, by module and level.",
+			func(stats Stats) map[logrus.Level]uint64 { return stats.Suppressed })
+		writeMetric("logrus_modular_entries_dropped_total", "Number of recorded entries discarded before being written, by module and level.",
+			func(stats Stats) map[logrus.Level]uint64 { return stats.Dropped })
+		bw.Flush()
+	})
+}
@@ -0,0 +1,64 @@
+package modular
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMetricsHandler(t *testing.T) {
+	logger := logrus.New()
+	logger.Out = bytes.NewBufferString("")
+	rl := NewRootLogger(logger)
+	payments := rl.GetOrCreateChild("payments", logrus.InfoLevel)
+	card := payments.GetOrCreateChild("card", logrus.InfoLevel)
+
+	card.Error("failed")
+	card.Error("failed")
+	payments.Debug("suppressed")
+
+	recorder := httptest.NewRecorder()
+	NewMetricsHandler(rl).ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	require.EqualValues(t, MetricsContentType, recorder.Header().Get("Content-Type"))
+
+	body := recorder.Body.String()
+	require.Contains(t, body, "# TYPE logrus_modular_entries_emitted_total counter\n")
+	require.Contains(t, body, "# TYPE logrus_modular_entries_suppressed_total counter\n")
+	require.Contains(t, body, "# TYPE logrus_modular_entries_dropped_total counter\n")
+	require.Contains(t, body, "logrus_modular_entries_emitted_total{module=\"payments.card\",level=\"error\"} 2\n")
+	require.Contains(t, body, "logrus_modular_entries_emitted_total{module=\"payments\",level=\"error\"} 0\n")
+	require.Contains(t, body, "logrus_modular_entries_suppressed_total{module=\"payments\",level=\"debug\"} 1\n")
+	require.Contains(t, body, "logrus_modular_entries_dropped_total{module=\"\",level=\"panic\"} 0\n")
+}
+
+func TestRegistry_MetricsHandler(t *testing.T) {
+	logger := logrus.New()
+	logger.Out = bytes.NewBufferString("")
+	tenantA := NewRootLogger(logger)
+	tenantB := NewRootLogger(logger)
+	r := NewRegistry()
+	require.NoError(t, r.Mount("tenantA", tenantA))
+	require.NoError(t, r.Mount("tenantB", tenantB))
+
+	tenantA.GetOrCreateChild("card", logrus.InfoLevel).Error("failed")
+	tenantB.GetOrCreateChild("card", logrus.InfoLevel).Debug("suppressed")
+
+	recorder := httptest.NewRecorder()
+	r.MetricsHandler().ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	require.EqualValues(t, MetricsContentType, recorder.Header().Get("Content-Type"))
+
+	body := recorder.Body.String()
+	require.EqualValues(t, 1, strings.Count(body, "# TYPE logrus_modular_entries_emitted_total counter\n"))
+	require.Contains(t, body, "logrus_modular_entries_emitted_total{module=\"tenantA.card\",level=\"error\"} 1\n")
+	require.Contains(t, body, "logrus_modular_entries_emitted_total{module=\"tenantB.card\",level=\"error\"} 0\n")
+	require.Contains(t, body, "logrus_modular_entries_suppressed_total{module=\"tenantB.card\",level=\"debug\"} 1\n")
+	require.Contains(t, body, "logrus_modular_entries_dropped_total{module=\"tenantA\",level=\"panic\"} 0\n")
+}
+
+func TestMetricsLabelEscaper(t *testing.T) {
+	require.EqualValues(t, `a\"b\\c\nd`, metricsLabelEscaper.Replace("a\"b\\c\nd"))
+}
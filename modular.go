@@ -3,10 +3,85 @@ package modular
 
 import "github.com/sirupsen/logrus"
 
-// NewRootLogger creates a new root logger, that wraps the passed logrus.Logger
+// NewRootLogger creates a new root logger, that wraps the passed logrus.Logger.
+// The logger's level is moved to the root module and the logger itself is set to DebugLevel,
+// see New for a constructor which leaves the logger untouched.
 func NewRootLogger(logger *logrus.Logger) RootLogger {
 	loggerLevel := logger.Level
 	logger.Level = logrus.DebugLevel
+
+	return newLoggerRoot(logger, loggerLevel)
+}
+
+// New creates a new root logger, that wraps the passed logrus.Logger and is configured by the given options.
+// Options are validated before the root logger is returned, the first invalid option yields an error.
+//
+// Unlike NewRootLogger, New leaves the logger untouched and gates levels purely inside the module tree,
+// with the root module starting out at the logger's current level. Entries of modules are written regardless
+// of the logger's own level, calling logger.SetLevel after construction only affects entries logged through
+// the logger directly and changes no module's level. Entries below the logger's level are formatted and
+// written by the root logger itself, as logrus cannot be asked to write them: the logger's hooks and formatter
+// are used, but its caller reporting is not and writes are not serialized with writes made by logrus.
+func New(logger *logrus.Logger, opts ...Option) (RootLogger, error) {
+	if logger == nil {
+		return nil, ErrNilLogger
+	}
+
+	c, err := newConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	if c.hasLoggerOptions() {
+		return nil, ErrStandaloneOption
+	}
+
+	lr := newLoggerRoot(logger, logger.GetLevel())
+	lr.gatesLevels = true
+	if err := c.apply(lr); err != nil {
+		return nil, err
+	}
+
+	return lr, nil
+}
+
+// NewStandalone creates a new root logger together with the logrus.Logger it wraps,
+// configured by the given options. The logger writes to os.Stderr using a logrus.TextFormatter,
+// unless WithOutput or WithFormatter are passed, and the root module starts out at InfoLevel.
+// As the logger is owned by the root logger, levels are gated by the module tree only.
+func NewStandalone(opts ...Option) (RootLogger, error) {
+	c, err := newConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.TraceLevel)
+	if c.output != nil {
+		logger.SetOutput(c.output)
+	}
+	if c.formatter != nil {
+		logger.SetFormatter(c.formatter)
+	}
+
+	lr := newLoggerRoot(logger, logrus.InfoLevel)
+	if err := c.apply(lr); err != nil {
+		return nil, err
+	}
+
+	return lr, nil
+}
+
+func newConfig(opts []Option) (*config, error) {
+	c := &config{}
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+func newLoggerRoot(logger *logrus.Logger, level logrus.Level) *loggerRoot {
 	lr := &loggerRoot{
 		logger:      logger,
 		moduleField: DefaultModuleField,
@@ -14,7 +89,7 @@ func NewRootLogger(logger *logrus.Logger) RootLogger {
 
 	lr.root = lr
 	lr.children = make(map[string]*loggerModule)
-	lr.level = loggerLevel
+	lr.level = level
 	lr.moduleLogger = lr
 
 	return lr
@@ -1,6 +1,9 @@
 package modular
 
 import (
+	"bytes"
+	"encoding/json"
+	"os"
 	"testing"
 
 	"github.com/sirupsen/logrus"
@@ -20,3 +23,135 @@ func TestNewRootLogger(t *testing.T) {
 	require.EqualValues(t, rl, rl.GetRoot())
 	require.EqualValues(t, rl, rl.GetModuleLogger())
 }
+
+func TestNew(t *testing.T) {
+	buffer := bytes.NewBufferString("")
+	logger := &logrus.Logger{
+		Out:       buffer,
+		Formatter: &logrus.JSONFormatter{},
+		Level:     logrus.InfoLevel,
+		Hooks:     make(logrus.LevelHooks),
+	}
+	rl, err := New(logger)
+	require.NoError(t, err)
+	require.NotNil(t, rl)
+	require.EqualValues(t, logger, rl.GetLogger())
+	require.EqualValues(t, DefaultModuleField, rl.GetModuleField())
+	require.EqualValues(t, rl, rl.GetRoot())
+
+	// The logger is left untouched, the root module starts out at its level
+	require.EqualValues(t, logrus.InfoLevel, logger.GetLevel())
+	require.EqualValues(t, logrus.InfoLevel, rl.GetLevel())
+
+	// Module levels gate entries...
+	module := rl.GetOrCreateChild("test", logrus.WarnLevel)
+	module.Info("test")
+	require.Empty(t, buffer.Bytes())
+	module.Warn("test")
+	require.NotEmpty(t, buffer.Bytes())
+
+	// The logger's level does not apply to modules, also when changed after construction
+	buffer.Reset()
+	module.SetLevel(logrus.DebugLevel)
+	module.Debug("test")
+	require.NotEmpty(t, buffer.Bytes())
+	require.EqualValues(t, logrus.InfoLevel, logger.GetLevel())
+
+	buffer.Reset()
+	logger.SetLevel(logrus.ErrorLevel)
+	module.Warn("test")
+	require.NotEmpty(t, buffer.Bytes())
+	require.EqualValues(t, logrus.DebugLevel, module.GetLevel())
+	require.EqualValues(t, logrus.InfoLevel, rl.GetLevel())
+
+	// ... but to entries logged through the logger directly
+	buffer.Reset()
+	logger.Warn("test")
+	require.Empty(t, buffer.Bytes())
+
+	buffer.Reset()
+	logger.SetLevel(logrus.TraceLevel)
+	rl.Debug("test")
+	require.Empty(t, buffer.Bytes())
+	logger.Debug("test")
+	require.NotEmpty(t, buffer.Bytes())
+}
+
+func TestNew_LoggerHooks(t *testing.T) {
+	buffer := bytes.NewBufferString("")
+	logger := &logrus.Logger{
+		Out:       buffer,
+		Formatter: &logrus.JSONFormatter{},
+		Level:     logrus.ErrorLevel,
+		Hooks:     make(logrus.LevelHooks),
+	}
+	hook := &recordingHook{}
+	logger.AddHook(hook)
+	rl, err := New(logger, WithLevel(logrus.DebugLevel))
+	require.NoError(t, err)
+
+	// Entries below the logger's level are written through its hooks and formatter
+	rl.WithField("key", "value").Debug("test")
+	require.Len(t, hook.entries, 1)
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(buffer.Bytes(), &entry))
+	require.EqualValues(t, "test", entry["msg"])
+	require.EqualValues(t, "debug", entry["level"])
+	require.EqualValues(t, "value", entry["key"])
+}
+
+func TestNew_Errors(t *testing.T) {
+	rl, err := New(nil)
+	require.Nil(t, rl)
+	require.EqualError(t, err, ErrNilLogger.Error())
+
+	rl, err = New(logrus.New(), WithLevel(logrus.Level(42)))
+	require.Nil(t, rl)
+	require.EqualError(t, err, ErrInvalidLevel.Error())
+}
+
+func TestNewStandalone(t *testing.T) {
+	rl, err := NewStandalone()
+	require.NoError(t, err)
+	require.EqualValues(t, os.Stderr, rl.GetLogger().Out)
+	require.IsType(t, &logrus.TextFormatter{}, rl.GetLogger().Formatter)
+	require.EqualValues(t, logrus.InfoLevel, rl.GetLevel())
+
+	buffer := bytes.NewBufferString("")
+	rl, err = NewStandalone(
+		WithOutput(buffer),
+		WithFormatter(&logrus.JSONFormatter{}),
+		WithLevel(logrus.WarnLevel),
+		WithLevelSpec("db=trace"),
+	)
+	require.NoError(t, err)
+	require.EqualValues(t, logrus.WarnLevel, rl.GetLevel())
+
+	rl.Info("test")
+	require.Empty(t, buffer.Bytes())
+
+	// Levels are gated by the module tree only
+	db, err := rl.GetChild("db")
+	require.NoError(t, err)
+	db.Debug("test")
+
+	var data map[string]interface{}
+	require.NoError(t, json.Unmarshal(buffer.Bytes(), &data))
+	require.EqualValues(t, "db", data["module"])
+
+	rl, err = NewStandalone(WithOutput(nil))
+	require.Nil(t, rl)
+	require.EqualError(t, err, ErrNilOutput.Error())
+
+	rl, err = NewStandalone(WithFormatter(nil))
+	require.Nil(t, rl)
+	require.EqualError(t, err, ErrNilFormatter.Error())
+}
+
+func TestNew_StandaloneOptions(t *testing.T) {
+	for _, opt := range []Option{WithOutput(os.Stdout), WithFormatter(&logrus.TextFormatter{})} {
+		rl, err := New(logrus.New(), opt)
+		require.Nil(t, rl)
+		require.EqualError(t, err, ErrStandaloneOption.Error())
+	}
+}
@@ -0,0 +1,192 @@
+// Package modulartest provides helpers for testing code which logs through a modular.RootLogger
+package modulartest
+
+import (
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/speijnik/logrus-modular.v1"
+)
+
+// Entry is a log entry captured by a Recorder
+type Entry struct {
+	// Module is the full name of the module which logged the entry
+	Module string
+	// Level is the level of the entry
+	Level logrus.Level
+	// Message is the message of the entry
+	Message string
+	// Fields holds the fields of the entry, excluding the module field
+	Fields logrus.Fields
+	// Time is the time at which the entry was logged
+	Time time.Time
+}
+
+// String returns a human readable representation of the entry
+func (e Entry) String() string {
+	return fmt.Sprintf("[%s] %s: %s %v", e.Level.String(), e.Module, e.Message, e.Fields)
+}
+
+// Recorder is a modular.RootLogger which records all entries in memory instead of writing them
+type Recorder struct {
+	modular.RootLogger
+
+	entriesMutex sync.Mutex
+	entries      []Entry
+}
+
+// New creates a new Recorder for the given test.
+// Each Recorder wraps its own logrus.Logger, so tests do not interfere with each other.
+// Fatal entries are recorded without exiting; captured entries are dumped through t.Log if the test fails.
+func New(t testing.TB) *Recorder {
+	logger := logrus.New()
+	logger.Out = ioutil.Discard
+	logger.Level = logrus.DebugLevel
+	logger.ExitFunc = func(int) {}
+
+	r := &Recorder{
+		RootLogger: modular.NewRootLogger(logger),
+	}
+	logger.AddHook(&recorderHook{recorder: r})
+
+	t.Cleanup(func() {
+		if t.Failed() {
+			r.dump(t)
+		}
+	})
+
+	return r
+}
+
+// Entries returns all captured entries in the order they were logged
+func (r *Recorder) Entries() []Entry {
+	r.entriesMutex.Lock()
+	defer r.entriesMutex.Unlock()
+
+	entries := make([]Entry, len(r.entries))
+	copy(entries, r.entries)
+	return entries
+}
+
+// Reset discards all captured entries
+func (r *Recorder) Reset() {
+	r.entriesMutex.Lock()
+	defer r.entriesMutex.Unlock()
+
+	r.entries = nil
+}
+
+// RequireLogged fails the test immediately, unless an entry has been logged by the given module at the given level,
+// with a message matching msgPattern and fields containing the given fields.
+// An empty msgPattern matches any message, nil fields match any fields.
+func (r *Recorder) RequireLogged(t testing.TB, module string, level logrus.Level, msgPattern string, fields logrus.Fields) {
+	t.Helper()
+
+	msgRegexp, err := regexp.Compile(msgPattern)
+	if err != nil {
+		t.Fatalf("Invalid message pattern %q: %s", msgPattern, err)
+		return
+	}
+
+	entries := r.Entries()
+	for _, entry := range entries {
+		if entry.Module == module && entry.Level == level && msgRegexp.MatchString(entry.Message) && containsFields(entry.Fields, fields) {
+			return
+		}
+	}
+
+	t.Fatalf("No %s entry by module %q matching %q with fields %v, captured entries:\n%s",
+		level.String(), module, msgPattern, fields, formatEntries(entries))
+}
+
+// NoEntriesAbove marks the test as failed if any entry has been logged at a level more severe than the given level,
+// i.e. NoEntriesAbove(t, logrus.WarnLevel) fails on Error, Fatal and Panic entries.
+func (r *Recorder) NoEntriesAbove(t testing.TB, level logrus.Level) {
+	t.Helper()
+
+	var offending []Entry
+	for _, entry := range r.Entries() {
+		if entry.Level < level {
+			offending = append(offending, entry)
+		}
+	}
+
+	if len(offending) > 0 {
+		t.Errorf("Expected no entries above %s, found:\n%s", level.String(), formatEntries(offending))
+	}
+}
+
+func (r *Recorder) record(entry *logrus.Entry) {
+	moduleField := r.GetModuleField()
+	fields := make(logrus.Fields, len(entry.Data))
+	for key, value := range entry.Data {
+		if key != moduleField {
+			fields[key] = value
+		}
+	}
+
+	module := ""
+	switch value := entry.Data[moduleField].(type) {
+	case string:
+		module = value
+	case []string:
+		module = strings.Join(value, r.GetSeparator())
+	}
+
+	r.entriesMutex.Lock()
+	defer r.entriesMutex.Unlock()
+
+	r.entries = append(r.entries, Entry{
+		Module:  module,
+		Level:   entry.Level,
+		Message: entry.Message,
+		Fields:  fields,
+		Time:    entry.Time,
+	})
+}
+
+func (r *Recorder) dump(t testing.TB) {
+	entries := r.Entries()
+	if len(entries) == 0 {
+		return
+	}
+	t.Logf("Captured log entries:\n%s", formatEntries(entries))
+}
+
+func containsFields(fields, expected logrus.Fields) bool {
+	for key, expectedValue := range expected {
+		value, ok := fields[key]
+		if !ok || !reflect.DeepEqual(value, expectedValue) {
+			return false
+		}
+	}
+	return true
+}
+
+func formatEntries(entries []Entry) string {
+	lines := make([]string, len(entries))
+	for i, entry := range entries {
+		lines[i] = "\t" + entry.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+type recorderHook struct {
+	recorder *Recorder
+}
+
+func (h *recorderHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *recorderHook) Fire(entry *logrus.Entry) error {
+	h.recorder.record(entry)
+	return nil
+}
@@ -0,0 +1,144 @@
+package modulartest
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeT records failures instead of failing the surrounding test
+type fakeT struct {
+	testing.TB
+
+	failed   bool
+	fatal    bool
+	logs     []string
+	cleanups []func()
+}
+
+func (f *fakeT) Helper() {}
+
+func (f *fakeT) Errorf(format string, args ...interface{}) {
+	f.failed = true
+	f.logs = append(f.logs, fmt.Sprintf(format, args...))
+}
+
+func (f *fakeT) Fatalf(format string, args ...interface{}) {
+	f.fatal = true
+	f.Errorf(format, args...)
+}
+
+func (f *fakeT) Logf(format string, args ...interface{}) {
+	f.logs = append(f.logs, fmt.Sprintf(format, args...))
+}
+
+func (f *fakeT) Failed() bool {
+	return f.failed
+}
+
+func (f *fakeT) Cleanup(fn func()) {
+	f.cleanups = append(f.cleanups, fn)
+}
+
+func (f *fakeT) runCleanups() {
+	for i := len(f.cleanups) - 1; i >= 0; i-- {
+		f.cleanups[i]()
+	}
+}
+
+func TestRecorder_Entries(t *testing.T) {
+	r := New(t)
+	module := r.GetOrCreateChild("db.query", logrus.InfoLevel)
+
+	module.Debug("suppressed")
+	module.WithField("rows", 3).Info("query executed")
+	module.WithError(errors.New("timeout")).Warn("query slow")
+
+	entries := r.Entries()
+	require.Len(t, entries, 2)
+	require.EqualValues(t, "db.query", entries[0].Module)
+	require.EqualValues(t, logrus.InfoLevel, entries[0].Level)
+	require.EqualValues(t, "query executed", entries[0].Message)
+	require.EqualValues(t, logrus.Fields{"rows": 3}, entries[0].Fields)
+	require.EqualValues(t, logrus.WarnLevel, entries[1].Level)
+
+	// Fatal does not exit
+	module.Fatal("fatal")
+	require.Len(t, r.Entries(), 3)
+
+	r.Reset()
+	require.Empty(t, r.Entries())
+}
+
+func TestRecorder_Isolation(t *testing.T) {
+	r1 := New(t)
+	r2 := New(t)
+
+	r1.GetOrCreateChild("test", logrus.InfoLevel).Info("test")
+	require.Len(t, r1.Entries(), 1)
+	require.Empty(t, r2.Entries())
+}
+
+func TestRecorder_RequireLogged(t *testing.T) {
+	r := New(t)
+	module := r.GetOrCreateChild("db.query", logrus.DebugLevel)
+	module.WithFields(logrus.Fields{"table": "users", "rows": 3}).Warn("query took 3s")
+
+	r.RequireLogged(t, "db.query", logrus.WarnLevel, `^query took \d+s$`, logrus.Fields{"table": "users"})
+	r.RequireLogged(t, "db.query", logrus.WarnLevel, "", nil)
+
+	for _, test := range []struct {
+		module     string
+		level      logrus.Level
+		msgPattern string
+		fields     logrus.Fields
+	}{
+		{"db", logrus.WarnLevel, "", nil},
+		{"db.query", logrus.ErrorLevel, "", nil},
+		{"db.query", logrus.WarnLevel, "^took", nil},
+		{"db.query", logrus.WarnLevel, "", logrus.Fields{"table": "orders"}},
+		{"db.query", logrus.WarnLevel, "", logrus.Fields{"missing": "users"}},
+		{"db.query", logrus.WarnLevel, "(", nil},
+	} {
+		ft := &fakeT{}
+		r.RequireLogged(ft, test.module, test.level, test.msgPattern, test.fields)
+		require.True(t, ft.fatal, "%+v", test)
+	}
+}
+
+func TestRecorder_NoEntriesAbove(t *testing.T) {
+	r := New(t)
+	module := r.GetOrCreateChild("test", logrus.DebugLevel)
+	module.Warn("warning")
+
+	ft := &fakeT{}
+	r.NoEntriesAbove(ft, logrus.WarnLevel)
+	require.False(t, ft.failed)
+
+	r.NoEntriesAbove(ft, logrus.ErrorLevel)
+	require.False(t, ft.failed)
+
+	module.Error("error")
+	r.NoEntriesAbove(ft, logrus.WarnLevel)
+	require.True(t, ft.failed)
+	require.False(t, ft.fatal)
+	require.Contains(t, ft.logs[0], "error")
+}
+
+func TestRecorder_DumpOnFailure(t *testing.T) {
+	ft := &fakeT{}
+	r := New(ft)
+	r.GetOrCreateChild("test", logrus.DebugLevel).Info("captured")
+
+	// Passing tests do not dump
+	ft.runCleanups()
+	require.Empty(t, ft.logs)
+
+	ft.failed = true
+	ft.runCleanups()
+	require.Len(t, ft.logs, 1)
+	require.Contains(t, ft.logs[0], "[info] test: captured")
+}
@@ -0,0 +1,130 @@
+package modulartest
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/speijnik/logrus-modular.v1"
+)
+
+// NewTestLogger creates a new modular.RootLogger which writes every entry through t.Log,
+// tagged with the module name and level and prefixed with the file and line of the logging call,
+// as reported through caller reporting. Output only shows up for failing or verbose tests
+// and stops once the test has ended.
+// Fatal and Panic entries fail the test through t.Fatal instead of exiting or panicking,
+// which requires them to be logged from the test's goroutine. Once the test has ended,
+// they are downgraded to Error entries of modules not configuring a FatalPolicy and dropped.
+func NewTestLogger(t testing.TB) modular.RootLogger {
+	tw := &testWriter{t: t}
+
+	logger := logrus.New()
+	logger.Out = tw
+	logger.Level = logrus.DebugLevel
+	logger.ExitFunc = func(int) {}
+
+	root := modular.NewRootLogger(logger)
+	root.SetReportCaller(true)
+	formatter := &testFormatter{root: root}
+	logger.Formatter = formatter
+	logger.AddHook(&testFatalHook{writer: tw, formatter: formatter})
+
+	t.Cleanup(func() {
+		tw.stop()
+		root.SetFatalPolicy(modular.FatalPolicy{Action: modular.FatalActionError})
+	})
+	return root
+}
+
+// testWriter writes lines through t.Log as long as the test is running
+type testWriter struct {
+	mutex   sync.Mutex
+	t       testing.TB
+	stopped bool
+}
+
+func (tw *testWriter) Write(p []byte) (int, error) {
+	tw.mutex.Lock()
+	defer tw.mutex.Unlock()
+
+	if !tw.stopped {
+		tw.t.Helper()
+		tw.t.Log(strings.TrimSuffix(string(p), "\n"))
+	}
+	return len(p), nil
+}
+
+// fatal fails the test with the given line as long as the test is running
+func (tw *testWriter) fatal(line string) {
+	tw.mutex.Lock()
+	if tw.stopped {
+		tw.mutex.Unlock()
+		return
+	}
+	tw.mutex.Unlock()
+
+	tw.t.Helper()
+	tw.t.Fatal(strings.TrimSuffix(line, "\n"))
+}
+
+func (tw *testWriter) stop() {
+	tw.mutex.Lock()
+	defer tw.mutex.Unlock()
+	tw.stopped = true
+}
+
+// testFormatter formats entries as "file.go:42: [level] module: message key=value ...",
+// the file and line being omitted if the caller is not reported
+type testFormatter struct {
+	root modular.RootLogger
+}
+
+func (f *testFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	moduleField := f.root.GetModuleField()
+
+	keys := make([]string, 0, len(entry.Data))
+	for key := range entry.Data {
+		switch key {
+		case moduleField, modular.CallerFileField, modular.CallerLineField, modular.CallerFuncField:
+		default:
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	buffer := &bytes.Buffer{}
+	if file, ok := entry.Data[modular.CallerFileField].(string); ok {
+		fmt.Fprintf(buffer, "%s:%v: ", filepath.Base(file), entry.Data[modular.CallerLineField])
+	}
+	fmt.Fprintf(buffer, "[%s] %v: %s", entry.Level.String(), entry.Data[moduleField], entry.Message)
+	for _, key := range keys {
+		fmt.Fprintf(buffer, " %s=%v", key, entry.Data[key])
+	}
+	buffer.WriteByte('\n')
+
+	return buffer.Bytes(), nil
+}
+
+// testFatalHook turns Fatal and Panic entries into t.Fatal
+type testFatalHook struct {
+	writer    *testWriter
+	formatter *testFormatter
+}
+
+func (h *testFatalHook) Levels() []logrus.Level {
+	return []logrus.Level{logrus.PanicLevel, logrus.FatalLevel}
+}
+
+func (h *testFatalHook) Fire(entry *logrus.Entry) error {
+	line, err := h.formatter.Format(entry)
+	if err != nil {
+		return err
+	}
+	h.writer.fatal(string(line))
+	return nil
+}
@@ -0,0 +1,99 @@
+package modulartest
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/speijnik/logrus-modular.v1"
+)
+
+// fakeFatalT extends fakeT by records of t.Log and t.Fatal, the latter ending the goroutine like testing.T does
+type fakeFatalT struct {
+	fakeT
+}
+
+func (f *fakeFatalT) Log(args ...interface{}) {
+	f.logs = append(f.logs, fmt.Sprint(args...))
+}
+
+func (f *fakeFatalT) Fatal(args ...interface{}) {
+	f.fatal = true
+	f.failed = true
+	f.Log(args...)
+	runtime.Goexit()
+}
+
+// runTest runs fn in its own goroutine, as the testing package does
+func runTest(fn func()) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn()
+	}()
+	<-done
+}
+
+func TestNewTestLogger(t *testing.T) {
+	ft := &fakeFatalT{}
+	root := NewTestLogger(ft)
+	module := root.GetOrCreateChild("db.query", logrus.InfoLevel)
+
+	module.Debug("suppressed")
+	_, _, line, _ := runtime.Caller(0)
+	module.WithFields(logrus.Fields{"rows": 3, "table": "users"}).Info("query executed")
+	// Lines are prefixed with the location of the logging call
+	require.EqualValues(t, []string{fmt.Sprintf("testing_test.go:%d: [info] db.query: query executed rows=3 table=users", line+1)}, ft.logs)
+	require.False(t, ft.failed)
+
+	// Works with a real testing.T as well
+	NewTestLogger(t).GetOrCreateChild("test", logrus.InfoLevel).Info("logged through t.Log")
+}
+
+func TestNewTestLogger_Fatal(t *testing.T) {
+	for _, logFn := range []func(modular.Logger){
+		func(l modular.Logger) { l.Fatal("fatal") },
+		func(l modular.Logger) { l.Panic("fatal") },
+	} {
+		ft := &fakeFatalT{}
+		root := NewTestLogger(ft)
+		module := root.GetOrCreateChild("test", logrus.InfoLevel)
+
+		reached := false
+		runTest(func() {
+			logFn(module)
+			reached = true
+		})
+
+		require.False(t, reached)
+		require.True(t, ft.fatal)
+		require.Len(t, ft.logs, 1)
+		require.Contains(t, ft.logs[0], "test: fatal")
+	}
+}
+
+func TestNewTestLogger_AfterTest(t *testing.T) {
+	ft := &fakeFatalT{}
+	root := NewTestLogger(ft)
+	module := root.GetOrCreateChild("test", logrus.InfoLevel)
+
+	module.Info("during test")
+	ft.runCleanups()
+
+	// Neither logs, fails nor panics once the test has ended, including from other goroutines
+	module.Info("after test")
+	module.Fatal("after test")
+	require.NotPanics(t, func() {
+		module.Panic("after test")
+	})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		module.Panic("after test")
+	}()
+	<-done
+	require.Len(t, ft.logs, 1)
+	require.False(t, ft.failed)
+}
@@ -0,0 +1,49 @@
+package modular
+
+import (
+	"strings"
+	"unicode"
+)
+
+// DefaultSeparator defines the default separator between module name segments
+const DefaultSeparator = "."
+
+const (
+	// MaxModuleNameLength defines the maximum length of a (full) module name in bytes
+	MaxModuleNameLength = 256
+	// MaxModuleDepth defines the maximum number of segments of a (full) module name
+	MaxModuleDepth = 32
+)
+
+// isModuleNameRune reports whether r may be part of a module name segment.
+// Segments consist of letters, digits, '_', '-' and '.', the latter being
+// usable within segments as long as it is not the separator.
+func isModuleNameRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-' || r == '.'
+}
+
+// splitModuleName validates the given module name and splits it into its segments
+func splitModuleName(moduleName, separator string) ([]string, error) {
+	if len(moduleName) > MaxModuleNameLength {
+		return nil, &ModuleNameError{Name: moduleName, Position: MaxModuleNameLength, Err: ErrModuleNameTooLong}
+	}
+
+	segments := strings.Split(moduleName, separator)
+	position := 0
+	for depth, segment := range segments {
+		if depth >= MaxModuleDepth {
+			return nil, &ModuleNameError{Name: moduleName, Position: position, Err: ErrModuleNameTooDeep}
+		}
+		if segment == "" {
+			return nil, &ModuleNameError{Name: moduleName, Position: position, Err: ErrEmptyModuleSegment}
+		}
+		for offset, r := range segment {
+			if !isModuleNameRune(r) {
+				return nil, &ModuleNameError{Name: moduleName, Position: position + offset, Err: ErrInvalidModuleCharacter}
+			}
+		}
+		position += len(segment) + len(separator)
+	}
+
+	return segments, nil
+}
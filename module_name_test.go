@@ -0,0 +1,51 @@
+package modular
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitModuleName(t *testing.T) {
+	segments, err := splitModuleName("a.b.c", ".")
+	require.NoError(t, err)
+	require.EqualValues(t, []string{"a", "b", "c"}, segments)
+
+	segments, err = splitModuleName("github.com/acme/x-y_z", "/")
+	require.NoError(t, err)
+	require.EqualValues(t, []string{"github.com", "acme", "x-y_z"}, segments)
+
+	segments, err = splitModuleName("a::b", "::")
+	require.NoError(t, err)
+	require.EqualValues(t, []string{"a", "b"}, segments)
+
+	tests := []struct {
+		name     string
+		position int
+		err      error
+	}{
+		{"", 0, ErrEmptyModuleSegment},
+		{".", 0, ErrEmptyModuleSegment},
+		{".a", 0, ErrEmptyModuleSegment},
+		{"a..b", 2, ErrEmptyModuleSegment},
+		{"a.", 2, ErrEmptyModuleSegment},
+		{"a.b c", 3, ErrInvalidModuleCharacter},
+		{"a.b/c", 3, ErrInvalidModuleCharacter},
+		{strings.Repeat("a", MaxModuleNameLength+1), MaxModuleNameLength, ErrModuleNameTooLong},
+		{strings.Repeat("a.", MaxModuleDepth) + "a", 2 * MaxModuleDepth, ErrModuleNameTooDeep},
+	}
+
+	for _, test := range tests {
+		segments, err = splitModuleName(test.name, ".")
+		require.Nil(t, segments)
+		require.True(t, errors.Is(err, ErrInvalidModuleName), "Module name %q", test.name)
+		require.True(t, errors.Is(err, test.err), "Module name %q", test.name)
+
+		nameErr, ok := err.(*ModuleNameError)
+		require.True(t, ok)
+		require.EqualValues(t, test.name, nameErr.Name)
+		require.EqualValues(t, test.position, nameErr.Position, "Module name %q", test.name)
+	}
+}
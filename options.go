@@ -0,0 +1,203 @@
+package modular
+
+import (
+	"io"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Option configures a root logger created by New
+type Option func(c *config) error
+
+// config collects the options passed to New
+type config struct {
+	level            *logrus.Level
+	moduleField      string
+	moduleFieldStyle ModuleFieldStyle
+	separator        string
+	levelSpec        LevelSpec
+	hooks            []logrus.Hook
+	clock            Clock
+	packageRules     []PackageRule
+	fatalPolicy      *FatalPolicy
+	reportCaller     bool
+
+	// Options for the logrus.Logger created by NewStandalone
+	output    io.Writer
+	formatter logrus.Formatter
+}
+
+// hasLoggerOptions reports whether options for the logrus.Logger created by NewStandalone are set
+func (c *config) hasLoggerOptions() bool {
+	return c.output != nil || c.formatter != nil
+}
+
+// apply applies the configuration to the given root logger
+func (c *config) apply(lr *loggerRoot) error {
+	if c.level != nil {
+		lr.level = *c.level
+	}
+	if c.moduleField != "" {
+		lr.SetModuleField(c.moduleField)
+	}
+	lr.SetModuleFieldStyle(c.moduleFieldStyle)
+	if c.separator != "" {
+		if err := lr.SetSeparator(c.separator); err != nil {
+			return err
+		}
+	}
+	for _, hook := range c.hooks {
+		lr.AddHook(hook)
+	}
+	if c.clock != nil {
+		lr.SetClock(c.clock)
+	}
+	if c.packageRules != nil {
+		lr.SetPackageRules(c.packageRules...)
+	}
+	if c.fatalPolicy != nil {
+		lr.SetFatalPolicy(*c.fatalPolicy)
+	}
+	if c.reportCaller {
+		lr.SetReportCaller(true)
+	}
+
+	// Applied last, as module creation depends on the separator
+	return c.levelSpec.Apply(lr)
+}
+
+// WithLevel sets the level of the root module, instead of the logger's current level
+func WithLevel(level logrus.Level) Option {
+	return func(c *config) error {
+		if !isValidLevel(level) {
+			return ErrInvalidLevel
+		}
+		c.level = &level
+		return nil
+	}
+}
+
+// WithModuleField sets the field holding the module name
+func WithModuleField(field string) Option {
+	return func(c *config) error {
+		if field == "" {
+			return ErrInvalidModuleField
+		}
+		c.moduleField = field
+		return nil
+	}
+}
+
+// WithModuleFieldStyle sets how the module name is represented in log entries
+func WithModuleFieldStyle(style ModuleFieldStyle) Option {
+	return func(c *config) error {
+		c.moduleFieldStyle = style
+		return nil
+	}
+}
+
+// WithSeparator sets the separator between module name segments
+func WithSeparator(separator string) Option {
+	return func(c *config) error {
+		if separator == "" {
+			return ErrInvalidSeparator
+		}
+		c.separator = separator
+		return nil
+	}
+}
+
+// WithLevelSpec applies the level spec, as parsed by ParseLevelSpec, once the root logger has been created.
+// Multiple level specs are applied in order.
+func WithLevelSpec(spec string) Option {
+	return func(c *config) error {
+		levelSpec, err := ParseLevelSpec(spec)
+		if err != nil {
+			return err
+		}
+		c.levelSpec = append(c.levelSpec, levelSpec...)
+		return nil
+	}
+}
+
+// WithHooks adds hooks which are fired for entries of the module tree only
+func WithHooks(hooks ...logrus.Hook) Option {
+	return func(c *config) error {
+		for _, hook := range hooks {
+			if hook == nil {
+				return ErrNilHook
+			}
+		}
+		c.hooks = append(c.hooks, hooks...)
+		return nil
+	}
+}
+
+// WithClock sets the clock providing entry timestamps
+func WithClock(clock Clock) Option {
+	return func(c *config) error {
+		if clock == nil {
+			return ErrNilClock
+		}
+		c.clock = clock
+		return nil
+	}
+}
+
+// WithPackageRules sets the rules used by ForPackage
+func WithPackageRules(rules ...PackageRule) Option {
+	return func(c *config) error {
+		c.packageRules = rules
+		return nil
+	}
+}
+
+// WithFatalPolicy sets the policy for Fatal and Panic entries of the module tree
+func WithFatalPolicy(policy FatalPolicy) Option {
+	return func(c *config) error {
+		c.fatalPolicy = &policy
+		return nil
+	}
+}
+
+// WithReportCaller enables reporting of the caller for the module tree
+func WithReportCaller() Option {
+	return func(c *config) error {
+		c.reportCaller = true
+		return nil
+	}
+}
+
+// WithOutput sets the output of the logrus.Logger created by NewStandalone.
+// Passing it to New results in ErrStandaloneOption.
+func WithOutput(output io.Writer) Option {
+	return func(c *config) error {
+		if output == nil {
+			return ErrNilOutput
+		}
+		c.output = output
+		return nil
+	}
+}
+
+// WithFormatter sets the formatter of the logrus.Logger created by NewStandalone.
+// Passing it to New results in ErrStandaloneOption.
+func WithFormatter(formatter logrus.Formatter) Option {
+	return func(c *config) error {
+		if formatter == nil {
+			return ErrNilFormatter
+		}
+		c.formatter = formatter
+		return nil
+	}
+}
+
+// isValidLevel reports whether level is one of logrus.AllLevels
+func isValidLevel(level logrus.Level) bool {
+	for _, validLevel := range logrus.AllLevels {
+		if level == validLevel {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+package modular
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+type fixedClock struct {
+	now time.Time
+}
+
+func (fc *fixedClock) Now() time.Time {
+	return fc.now
+}
+
+type recordingHook struct {
+	entries []*logrus.Entry
+}
+
+func (rh *recordingHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (rh *recordingHook) Fire(entry *logrus.Entry) error {
+	rh.entries = append(rh.entries, entry)
+	return nil
+}
+
+func TestWithLevel(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	rl, err := New(logger, WithLevel(logrus.DebugLevel))
+	require.NoError(t, err)
+	require.EqualValues(t, logrus.DebugLevel, rl.GetLevel())
+	require.EqualValues(t, logrus.ErrorLevel, logger.GetLevel())
+
+	require.EqualError(t, WithLevel(logrus.Level(42))(&config{}), ErrInvalidLevel.Error())
+}
+
+func TestOptions(t *testing.T) {
+	buffer := bytes.NewBufferString("")
+	logger := &logrus.Logger{
+		Out:       buffer,
+		Formatter: &logrus.JSONFormatter{},
+		Level:     logrus.DebugLevel,
+		Hooks:     make(logrus.LevelHooks),
+	}
+	hook := &recordingHook{}
+	clock := &fixedClock{now: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)}
+
+	rl, err := New(logger,
+		WithLevelSpec("info,github.com/acme=debug"),
+		WithModuleField("component"),
+		WithModuleFieldStyle(ModuleFieldStyleLeaf),
+		WithSeparator("/"),
+		WithHooks(hook),
+		WithClock(clock),
+		WithPackageRules(StripPackagePrefix("github.com")),
+		WithFatalPolicy(FatalPolicy{Action: FatalActionError}),
+		WithReportCaller(),
+	)
+	require.NoError(t, err)
+
+	require.EqualValues(t, "component", rl.GetModuleField())
+	require.EqualValues(t, ModuleFieldStyleLeaf, rl.GetModuleFieldStyle())
+	require.EqualValues(t, "/", rl.GetSeparator())
+	require.EqualValues(t, clock, rl.GetClock())
+	require.EqualValues(t, FatalPolicy{Action: FatalActionError}, rl.GetFatalPolicy())
+	require.True(t, rl.GetReportCaller())
+	require.EqualValues(t, logrus.InfoLevel, rl.GetLevel())
+
+	// Level spec is applied after the separator has been set
+	module, err := rl.GetChild("github.com/acme")
+	require.NoError(t, err)
+	require.EqualValues(t, logrus.DebugLevel, module.GetLevel())
+
+	module.Debug("test")
+	require.Len(t, hook.entries, 1)
+	require.EqualValues(t, "test", hook.entries[0].Message)
+	require.EqualValues(t, logrus.DebugLevel, hook.entries[0].Level)
+
+	var data map[string]interface{}
+	require.NoError(t, json.Unmarshal(buffer.Bytes(), &data))
+	require.EqualValues(t, "acme", data["component"])
+	require.EqualValues(t, "2020-01-02T03:04:05Z", data["time"])
+
+	// Hooks are not added to the wrapped logger
+	require.Empty(t, logger.Hooks)
+}
+
+func TestOptions_Errors(t *testing.T) {
+	tests := []struct {
+		opt Option
+		err error
+	}{
+		{WithLevel(logrus.Level(42)), ErrInvalidLevel},
+		{WithModuleField(""), ErrInvalidModuleField},
+		{WithSeparator(""), ErrInvalidSeparator},
+		{WithLevelSpec("db=verbose"), ErrInvalidLevelSpec},
+		{WithLevelSpec("db..x=debug"), ErrInvalidModuleName},
+		{WithHooks(&recordingHook{}, nil), ErrNilHook},
+		{WithClock(nil), ErrNilClock},
+	}
+
+	for _, test := range tests {
+		rl, err := New(logrus.New(), test.opt)
+		require.Nil(t, rl)
+		require.True(t, errors.Is(err, test.err), "Expected %v, got %v", test.err, err)
+	}
+}
@@ -0,0 +1,196 @@
+package modular
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+var _ LevelOverride = (*levelOverride)(nil)
+
+// pendingOverrides counts the overrides held by all modules, including expired ones which have not been pruned yet,
+// allowing overriddenLevel to skip walking the ancestors if there are none.
+// Overrides are pruned once their duration has elapsed and when their module is removed.
+var pendingOverrides int64
+
+type levelOverride struct {
+	module *loggerModule
+	level  logrus.Level
+	expiry time.Time
+	timer  *time.Timer
+
+	cancelledMutex sync.Mutex
+	cancelled      bool
+}
+
+func (lo *levelOverride) GetLevel() logrus.Level {
+	return lo.level
+}
+
+func (lo *levelOverride) GetExpiry() time.Time {
+	return lo.expiry
+}
+
+func (lo *levelOverride) IsActive() bool {
+	lo.cancelledMutex.Lock()
+	cancelled := lo.cancelled
+	lo.cancelledMutex.Unlock()
+
+	return !cancelled && lo.module.now().Before(lo.expiry)
+}
+
+func (lo *levelOverride) Cancel() {
+	lo.cancelledMutex.Lock()
+	lo.cancelled = true
+	lo.cancelledMutex.Unlock()
+	lo.timer.Stop()
+
+	lo.module.expireLevelOverrides()
+}
+
+// now returns the current time according to the root's clock
+func (lm *loggerModule) now() time.Time {
+	if lm.root == nil {
+		return SystemClock.Now()
+	}
+	return lm.root.GetClock().Now()
+}
+
+func (lm *loggerModule) SetLevelFor(level logrus.Level, duration time.Duration) (LevelOverride, error) {
+	if !isValidLevel(level) {
+		return nil, ErrInvalidLevel
+	}
+	if duration <= 0 {
+		return nil, ErrInvalidDuration
+	}
+
+	oldLevel := lm.GetLevel()
+	override := &levelOverride{
+		module: lm,
+		level:  level,
+		expiry: lm.now().Add(duration),
+		timer:  time.AfterFunc(duration, lm.expireLevelOverrides),
+	}
+
+	lm.overridesMutex.Lock()
+	lm.overrides = append(lm.overrides, override)
+	atomic.AddInt64(&pendingOverrides, 1)
+	lm.overridesMutex.Unlock()
+
+	lm.publishOverride(oldLevel, level)
+	return override, nil
+}
+
+func (lm *loggerModule) GetLevelOverrides() []LevelOverride {
+	lm.overridesMutex.Lock()
+	endedLevel, ended := lm.pruneLevelOverrides()
+	overrides := make([]LevelOverride, len(lm.overrides))
+	for i, override := range lm.overrides {
+		overrides[i] = override
+	}
+	lm.overridesMutex.Unlock()
+
+	if ended {
+		lm.publishOverrideEnded(endedLevel)
+	}
+	return overrides
+}
+
+// overriddenLevel returns the level of the most recent active override of the module or,
+// if there is none, of its closest ancestor with an active override
+func (lm *loggerModule) overriddenLevel() (logrus.Level, bool) {
+	if atomic.LoadInt64(&pendingOverrides) == 0 {
+		return 0, false
+	}
+
+	for module := lm; module != nil; module = module.parent {
+		module.overridesMutex.Lock()
+		endedLevel, ended := module.pruneLevelOverrides()
+		count := len(module.overrides)
+		var level logrus.Level
+		if count > 0 {
+			level = module.overrides[count-1].level
+		}
+		module.overridesMutex.Unlock()
+
+		if ended {
+			module.publishOverrideEnded(endedLevel)
+		}
+		if count > 0 {
+			return level, true
+		}
+	}
+	return 0, false
+}
+
+// pruneLevelOverrides removes expired and cancelled overrides, overridesMutex must be held.
+// If the most recent override has been removed, returns the level it had set.
+func (lm *loggerModule) pruneLevelOverrides() (endedLevel logrus.Level, ended bool) {
+	if len(lm.overrides) == 0 {
+		return 0, false
+	}
+
+	latest := lm.overrides[len(lm.overrides)-1]
+	active := lm.overrides[:0]
+	for _, override := range lm.overrides {
+		if override.IsActive() {
+			active = append(active, override)
+		}
+	}
+	for i := len(active); i < len(lm.overrides); i++ {
+		lm.overrides[i] = nil
+	}
+	atomic.AddInt64(&pendingOverrides, -int64(len(lm.overrides)-len(active)))
+	lm.overrides = active
+
+	if len(active) == 0 || active[len(active)-1] != latest {
+		return latest.level, true
+	}
+	return 0, false
+}
+
+// expireLevelOverrides prunes the module's overrides once the duration of one of them has elapsed
+// or one of them has been cancelled.
+// Overrides expiring according to a clock other than SystemClock are pruned when the level is read.
+func (lm *loggerModule) expireLevelOverrides() {
+	lm.overridesMutex.Lock()
+	endedLevel, ended := lm.pruneLevelOverrides()
+	lm.overridesMutex.Unlock()
+
+	if ended {
+		lm.publishOverrideEnded(endedLevel)
+	}
+}
+
+// publishOverrideEnded publishes the level change caused by the end of the override which had set the given level
+func (lm *loggerModule) publishOverrideEnded(endedLevel logrus.Level) {
+	lm.publishOverride(endedLevel, lm.GetLevel())
+}
+
+// publishOverride publishes a change of the module's level caused by an override taking effect or ending
+func (lm *loggerModule) publishOverride(oldLevel, newLevel logrus.Level) {
+	if oldLevel == newLevel {
+		return
+	}
+	lm.publish([]Event{{
+		Type:     EventLevelChanged,
+		Module:   lm.name,
+		OldLevel: oldLevel,
+		NewLevel: newLevel,
+		Source:   EventSourceOverride,
+	}})
+}
+
+// clearLevelOverrides removes all overrides of the module, once it has been removed from the tree
+func (lm *loggerModule) clearLevelOverrides() {
+	lm.overridesMutex.Lock()
+	defer lm.overridesMutex.Unlock()
+
+	for _, override := range lm.overrides {
+		override.timer.Stop()
+	}
+	atomic.AddInt64(&pendingOverrides, -int64(len(lm.overrides)))
+	lm.overrides = nil
+}
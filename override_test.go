@@ -0,0 +1,127 @@
+package modular
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerModule_SetLevelFor(t *testing.T) {
+	clock := &fixedClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+	rl, err := New(logrus.New(), WithLevel(logrus.InfoLevel), WithClock(clock))
+	require.NoError(t, err)
+	module := rl.GetOrCreateChild("db", logrus.WarnLevel)
+	child := module.GetOrCreateChild("sql", logrus.ErrorLevel)
+
+	override, err := module.SetLevelFor(logrus.DebugLevel, 10*time.Minute)
+	require.NoError(t, err)
+	require.EqualValues(t, logrus.DebugLevel, override.GetLevel())
+	require.EqualValues(t, clock.now.Add(10*time.Minute), override.GetExpiry())
+	require.True(t, override.IsActive())
+
+	// Applies to the module and its children, but not to the parent
+	require.EqualValues(t, logrus.DebugLevel, module.GetLevel())
+	require.EqualValues(t, logrus.DebugLevel, child.GetLevel())
+	require.EqualValues(t, logrus.InfoLevel, rl.GetLevel())
+	require.EqualValues(t, []LevelOverride{override}, module.GetLevelOverrides())
+	require.Empty(t, child.GetLevelOverrides())
+
+	// Reverts once expired
+	clock.now = clock.now.Add(10 * time.Minute)
+	require.False(t, override.IsActive())
+	require.EqualValues(t, logrus.WarnLevel, module.GetLevel())
+	require.EqualValues(t, logrus.ErrorLevel, child.GetLevel())
+	require.Empty(t, module.GetLevelOverrides())
+}
+
+func TestLoggerModule_SetLevelFor_Nested(t *testing.T) {
+	clock := &fixedClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+	rl, err := New(logrus.New(), WithClock(clock))
+	require.NoError(t, err)
+	module := rl.GetOrCreateChild("db", logrus.WarnLevel)
+	child := module.GetOrCreateChild("sql", logrus.ErrorLevel)
+
+	outer, err := module.SetLevelFor(logrus.InfoLevel, 10*time.Minute)
+	require.NoError(t, err)
+	inner, err := module.SetLevelFor(logrus.DebugLevel, time.Minute)
+	require.NoError(t, err)
+	childOverride, err := child.SetLevelFor(logrus.TraceLevel, 5*time.Minute)
+	require.NoError(t, err)
+
+	require.EqualValues(t, logrus.DebugLevel, module.GetLevel())
+	require.EqualValues(t, logrus.TraceLevel, child.GetLevel())
+	require.EqualValues(t, []LevelOverride{outer, inner}, module.GetLevelOverrides())
+
+	// Inner expires, the outer override applies again
+	clock.now = clock.now.Add(time.Minute)
+	require.EqualValues(t, logrus.InfoLevel, module.GetLevel())
+
+	// Child override expires, the inherited override applies
+	clock.now = clock.now.Add(4 * time.Minute)
+	require.False(t, childOverride.IsActive())
+	require.EqualValues(t, logrus.InfoLevel, child.GetLevel())
+
+	// Own level changes do not affect active overrides
+	module.SetLevel(logrus.ErrorLevel)
+	require.EqualValues(t, logrus.InfoLevel, module.GetLevel())
+
+	// Cancelling reverts to the own level
+	outer.Cancel()
+	require.False(t, outer.IsActive())
+	require.EqualValues(t, logrus.ErrorLevel, module.GetLevel())
+	require.EqualValues(t, logrus.ErrorLevel, child.GetLevel())
+	require.Empty(t, module.GetLevelOverrides())
+}
+
+func TestLoggerModule_SetLevelFor_Cancel(t *testing.T) {
+	lm := &loggerModule{
+		level:    logrus.WarnLevel,
+		children: make(map[string]*loggerModule),
+	}
+
+	first, err := lm.SetLevelFor(logrus.InfoLevel, time.Hour)
+	require.NoError(t, err)
+	second, err := lm.SetLevelFor(logrus.DebugLevel, time.Hour)
+	require.NoError(t, err)
+
+	// Cancelling an outer override keeps the inner one in effect
+	first.Cancel()
+	require.EqualValues(t, logrus.DebugLevel, lm.GetLevel())
+	require.EqualValues(t, []LevelOverride{second}, lm.GetLevelOverrides())
+
+	second.Cancel()
+	second.Cancel()
+	require.EqualValues(t, logrus.WarnLevel, lm.GetLevel())
+
+	_, err = lm.SetLevelFor(logrus.DebugLevel, 0)
+	require.EqualError(t, err, ErrInvalidDuration.Error())
+	_, err = lm.SetLevelFor(logrus.Level(42), time.Hour)
+	require.EqualError(t, err, ErrInvalidLevel.Error())
+}
+
+func TestLoggerModule_SetLevelFor_Pending(t *testing.T) {
+	rl := NewRootLogger(logrus.New())
+	module := rl.GetOrCreateChild("db", logrus.InfoLevel)
+	pending := atomic.LoadInt64(&pendingOverrides)
+
+	// Overrides are pruned once expired, without reading the level
+	_, err := module.SetLevelFor(logrus.DebugLevel, 10*time.Millisecond)
+	require.NoError(t, err)
+	require.EqualValues(t, pending+1, atomic.LoadInt64(&pendingOverrides))
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt64(&pendingOverrides) == pending
+	}, time.Second, time.Millisecond)
+
+	// Overrides of removed modules are released
+	child := module.GetOrCreateChild("sql", logrus.InfoLevel)
+	_, err = child.SetLevelFor(logrus.DebugLevel, time.Hour)
+	require.NoError(t, err)
+	_, err = module.SetLevelFor(logrus.DebugLevel, time.Hour)
+	require.NoError(t, err)
+	require.EqualValues(t, pending+2, atomic.LoadInt64(&pendingOverrides))
+	require.NoError(t, rl.RemoveChild("db"))
+	require.EqualValues(t, pending, atomic.LoadInt64(&pendingOverrides))
+}
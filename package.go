@@ -0,0 +1,108 @@
+package modular
+
+import (
+	"runtime"
+	"strings"
+)
+
+// PackageRule maps a package import path to another import path before it is turned into a module name
+type PackageRule func(importPath string) string
+
+// DefaultPackageRules defines the rules used by ForPackage if no rules have been set
+var DefaultPackageRules = []PackageRule{StripPackageDomain()}
+
+// StripPackagePrefix returns a PackageRule which strips the given prefix from import paths,
+// i.e. StripPackagePrefix("github.com/acme") maps "github.com/acme/svc/db" to "svc/db".
+// Only whole path elements are stripped.
+func StripPackagePrefix(prefix string) PackageRule {
+	return ReplacePackagePrefix(prefix, "")
+}
+
+// ReplacePackagePrefix returns a PackageRule which replaces the given prefix of import paths,
+// i.e. ReplacePackagePrefix("github.com/acme", "acme") maps "github.com/acme/svc/db" to "acme/svc/db".
+// Only whole path elements are replaced.
+func ReplacePackagePrefix(prefix, replacement string) PackageRule {
+	prefix = strings.Trim(prefix, "/")
+	replacement = strings.Trim(replacement, "/")
+	return func(importPath string) string {
+		if importPath != prefix && !strings.HasPrefix(importPath, prefix+"/") {
+			return importPath
+		}
+		return strings.Trim(replacement+strings.TrimPrefix(importPath, prefix), "/")
+	}
+}
+
+// StripPackageDomain returns a PackageRule which strips the leading path element of import paths,
+// if it is a domain, i.e. "github.com/acme/svc" is mapped to "acme/svc".
+func StripPackageDomain() PackageRule {
+	return func(importPath string) string {
+		elements := strings.SplitN(importPath, "/", 2)
+		if len(elements) < 2 || !strings.Contains(elements[0], ".") {
+			return importPath
+		}
+		return elements[1]
+	}
+}
+
+// callerPackage returns the import path of the package of the function skip frames above the caller
+func callerPackage(skip int) string {
+	pc, _, _, ok := runtime.Caller(skip + 1)
+	if !ok {
+		return ""
+	}
+	fn := runtime.FuncForPC(pc)
+	if fn == nil {
+		return ""
+	}
+	return packageFromFuncName(fn.Name())
+}
+
+// packageFromFuncName extracts the import path from a fully qualified function name,
+// i.e. "github.com/acme/svc/db.(*Conn).Query" yields "github.com/acme/svc/db".
+func packageFromFuncName(funcName string) string {
+	lastSlash := strings.LastIndex(funcName, "/")
+	if dot := strings.Index(funcName[lastSlash+1:], "."); dot >= 0 {
+		funcName = funcName[:lastSlash+1+dot]
+	}
+	return unescapeFuncName(funcName)
+}
+
+// unescapeFuncName unescapes dots within the last path element of a function name, which are escaped by the linker
+func unescapeFuncName(funcName string) string {
+	return strings.Replace(funcName, "%2e", ".", -1)
+}
+
+// packageModuleName maps the given import path to a module name by applying the rules
+// and joining the remaining path elements with the given separator, each path element becoming one segment.
+// Characters not allowed in module names are replaced by '_', as are characters of the separator.
+// Trailing path elements exceeding MaxModuleDepth or MaxModuleNameLength are dropped.
+func packageModuleName(importPath string, rules []PackageRule, separator string) string {
+	for _, rule := range rules {
+		importPath = rule(importPath)
+	}
+
+	var segments []string
+	length := 0
+	for _, element := range strings.Split(importPath, "/") {
+		if element == "" {
+			continue
+		}
+		segment := strings.Map(func(r rune) rune {
+			if isModuleNameRune(r) && !strings.ContainsRune(separator, r) {
+				return r
+			}
+			return '_'
+		}, element)
+
+		if len(segments) > 0 {
+			length += len(separator)
+		}
+		length += len(segment)
+		if len(segments) >= MaxModuleDepth || (len(segments) > 0 && length > MaxModuleNameLength) {
+			break
+		}
+		segments = append(segments, segment)
+	}
+
+	return strings.Join(segments, separator)
+}
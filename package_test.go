@@ -0,0 +1,93 @@
+package modular
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStripPackagePrefix(t *testing.T) {
+	rule := StripPackagePrefix("github.com/acme/")
+	require.EqualValues(t, "svc/internal/db", rule("github.com/acme/svc/internal/db"))
+	require.EqualValues(t, "", rule("github.com/acme"))
+	require.EqualValues(t, "github.com/acmecorp/svc", rule("github.com/acmecorp/svc"))
+	require.EqualValues(t, "example.com/svc", rule("example.com/svc"))
+}
+
+func TestReplacePackagePrefix(t *testing.T) {
+	rule := ReplacePackagePrefix("github.com/acme", "acme")
+	require.EqualValues(t, "acme/svc", rule("github.com/acme/svc"))
+	require.EqualValues(t, "acme", rule("github.com/acme"))
+	require.EqualValues(t, "github.com/other/svc", rule("github.com/other/svc"))
+}
+
+func TestStripPackageDomain(t *testing.T) {
+	rule := StripPackageDomain()
+	require.EqualValues(t, "acme/svc", rule("github.com/acme/svc"))
+	require.EqualValues(t, "net/http", rule("net/http"))
+	require.EqualValues(t, "main", rule("main"))
+}
+
+func TestPackageFromFuncName(t *testing.T) {
+	require.EqualValues(t, "github.com/acme/svc/db", packageFromFuncName("github.com/acme/svc/db.(*Conn).Query"))
+	require.EqualValues(t, "github.com/acme/svc/db", packageFromFuncName("github.com/acme/svc/db.init.0"))
+	require.EqualValues(t, "gopkg.in/acme/lib.v1", packageFromFuncName("gopkg.in/acme/lib%2ev1.New.func1"))
+	require.EqualValues(t, "main", packageFromFuncName("main.main"))
+}
+
+func TestUnescapeFuncName(t *testing.T) {
+	require.EqualValues(t, "gopkg.in/acme/lib.v1.New.func1", unescapeFuncName("gopkg.in/acme/lib%2ev1.New.func1"))
+	require.EqualValues(t, "main.main", unescapeFuncName("main.main"))
+}
+
+func TestPackageModuleName(t *testing.T) {
+	rules := []PackageRule{StripPackagePrefix("github.com/acme")}
+	require.EqualValues(t, "svc.internal.db", packageModuleName("github.com/acme/svc/internal/db", rules, "."))
+	require.EqualValues(t, "svc/internal/db", packageModuleName("github.com/acme/svc/internal/db", rules, "/"))
+	require.EqualValues(t, "", packageModuleName("github.com/acme", rules, "."))
+
+	// Each path element becomes one segment, separator and invalid characters are replaced
+	require.EqualValues(t, "acme.lib_v1.sub", packageModuleName("gopkg.in/acme/lib.v1/sub", DefaultPackageRules, "."))
+	require.EqualValues(t, "acme::lib.v1", packageModuleName("gopkg.in/acme/lib.v1", DefaultPackageRules, "::"))
+	require.EqualValues(t, "acme/lib_v1", packageModuleName("gopkg.in/acme/lib~v1", DefaultPackageRules, "/"))
+
+	// Path elements beyond the limits are dropped
+	deep := strings.Repeat("a/", MaxModuleDepth+5) + "a"
+	require.EqualValues(t, strings.Repeat("a.", MaxModuleDepth-1)+"a", packageModuleName(deep, nil, "."))
+	long := strings.Repeat("abcdefghij/", MaxModuleNameLength/10)
+	moduleName := packageModuleName(long, nil, ".")
+	require.True(t, len(moduleName) <= MaxModuleNameLength)
+	require.True(t, strings.HasPrefix(moduleName, "abcdefghij.abcdefghij"))
+	require.EqualValues(t, "", packageModuleName("", nil, "."))
+}
+
+func TestLoggerRoot_ForPackage(t *testing.T) {
+	importPath := reflect.TypeOf(loggerRoot{}).PkgPath()
+
+	rl := NewRootLogger(logrus.New())
+	rl.SetLevel(logrus.WarnLevel)
+
+	module := rl.ForPackage()
+	require.NotNil(t, module)
+	require.EqualValues(t, packageModuleName(importPath, DefaultPackageRules, "."), module.GetModuleName())
+	require.EqualValues(t, logrus.WarnLevel, module.GetLevel())
+
+	// Subsequent calls return the same module
+	require.EqualValues(t, module, rl.ForPackage())
+
+	// Custom rules
+	rl.SetPackageRules(ReplacePackagePrefix(importPath, "self"))
+	module = rl.ForPackage()
+	require.EqualValues(t, "self", module.GetModuleName())
+
+	// Stripping the whole import path yields the root
+	rl.SetPackageRules(StripPackagePrefix(importPath))
+	require.EqualValues(t, rl, rl.ForPackage())
+
+	// As do import paths no module can be created for
+	rl.SetPackageRules(ReplacePackagePrefix(importPath, strings.Repeat("a", MaxModuleNameLength+1)))
+	require.EqualValues(t, rl, rl.ForPackage())
+}
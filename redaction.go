@@ -0,0 +1,313 @@
+package modular
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// RedactedValue defines the replacement of redacted values
+const RedactedValue = "[REDACTED]"
+
+// maxRedactionDepth limits how deep nested maps, structs, slices and pointers are inspected
+const maxRedactionDepth = 10
+
+var (
+	// RedactCardNumbers redacts sequences of 13 to 19 digits, optionally separated by spaces or dashes
+	RedactCardNumbers = RedactionRule{value: regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`)}
+	// RedactEmails redacts email addresses
+	RedactEmails = RedactionRule{value: regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)}
+)
+
+// Redactor is implemented by values which provide a redacted representation of themselves.
+// Redactor values are replaced by the result of Redact in all modules.
+type Redactor interface {
+	Redact() interface{}
+}
+
+// RedactionRule defines which fields are redacted before an entry reaches formatters and hooks.
+// Rules apply to the fields of an entry and to the keys and values of nested maps, structs and slices,
+// value rules also apply to the message.
+type RedactionRule struct {
+	key     string
+	keyGlob string
+	value   *regexp.Regexp
+}
+
+// RedactKey returns a rule redacting the values of all fields with the given key, ignoring case
+func RedactKey(key string) RedactionRule {
+	return RedactionRule{key: key}
+}
+
+// RedactKeyGlob returns a rule redacting the values of all fields whose key matches the given pattern,
+// as understood by path.Match and ignoring case, i.e. "*_token"
+func RedactKeyGlob(pattern string) (RedactionRule, error) {
+	if pattern == "" {
+		return RedactionRule{}, ErrInvalidPattern
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return RedactionRule{}, ErrInvalidPattern
+	}
+	return RedactionRule{keyGlob: strings.ToLower(pattern)}, nil
+}
+
+// RedactValue returns a rule replacing all matches of the given regular expression in string values,
+// including values of named string types and byte slices, which keep their type, and error messages
+func RedactValue(pattern string) (RedactionRule, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil || pattern == "" {
+		return RedactionRule{}, ErrInvalidPattern
+	}
+	return RedactionRule{value: re}, nil
+}
+
+// String returns a description of the rule
+func (rr RedactionRule) String() string {
+	switch {
+	case rr.value != nil:
+		return fmt.Sprintf("value:%s", rr.value.String())
+	case rr.keyGlob != "":
+		return fmt.Sprintf("glob:%s", rr.keyGlob)
+	}
+	return fmt.Sprintf("key:%s", rr.key)
+}
+
+// matchesKey reports whether the rule redacts the value of the given key
+func (rr RedactionRule) matchesKey(key string) bool {
+	if rr.keyGlob != "" {
+		matched, _ := path.Match(rr.keyGlob, strings.ToLower(key))
+		return matched
+	}
+	return rr.key != "" && strings.EqualFold(rr.key, key)
+}
+
+func (lm *loggerModule) SetRedactionRules(rules ...RedactionRule) {
+	lm.settingsMutex.Lock()
+	defer lm.settingsMutex.Unlock()
+	lm.redactionRules = append([]RedactionRule(nil), rules...)
+	invalidateSettings()
+}
+
+func (lm *loggerModule) GetRedactionRules() []RedactionRule {
+	return append([]RedactionRule(nil), lm.resolveSettings().redactionRules...)
+}
+
+// redactedError wraps an error whose message has been redacted.
+// Formatters render the redacted message, errors.Is and errors.As still match the wrapped error.
+type redactedError struct {
+	message string
+	err     error
+}
+
+func (re *redactedError) Error() string {
+	return re.message
+}
+
+func (re *redactedError) Unwrap() error {
+	return re.err
+}
+
+// redaction redacts field values according to the rules of a module
+type redaction struct {
+	moduleLogger ModuleLogger
+	rules        []RedactionRule
+	reveal       bool
+}
+
+func newRedaction(moduleLogger ModuleLogger, settings *resolvedSettings) *redaction {
+	return &redaction{
+		moduleLogger: moduleLogger,
+		rules:        settings.redactionRules,
+		reveal:       settings.revealSecrets,
+	}
+}
+
+// redact returns the redacted value of the given field
+func (r *redaction) redact(key string, value interface{}) interface{} {
+	value, _ = r.redactField(key, value, 0)
+	return value
+}
+
+// redactField returns the redacted value of the given field and whether the value has changed
+func (r *redaction) redactField(key string, value interface{}, depth int) (interface{}, bool) {
+	for _, rule := range r.rules {
+		if rule.matchesKey(key) {
+			return RedactedValue, true
+		}
+	}
+	return r.redactValue(value, depth)
+}
+
+// redactValue returns the redacted value and whether the value has changed
+func (r *redaction) redactValue(value interface{}, depth int) (interface{}, bool) {
+	switch v := value.(type) {
+	case nil:
+		return nil, false
+	case SecretValue:
+		if r.reveal {
+			value, _ := r.redactValue(v.value, depth+1)
+			return value, true
+		}
+		return RedactedValue, true
+	case HashedValue:
+		if r.reveal {
+			value, _ := r.redactValue(v.value, depth+1)
+			return value, true
+		}
+		return v.hash(hashSaltOf(r.moduleLogger.GetRoot())), true
+	case Redactor:
+		return v.Redact(), true
+	case string:
+		if redacted, changed := r.redactString(v); changed {
+			return redacted, true
+		}
+		return value, false
+	case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return value, false
+	case error:
+		// Errors are rendered through their message by formatters, which must not be replaced by a map.
+		// Errors whose message is matched by value rules are wrapped, so that hooks still receive an error.
+		if redacted, changed := r.redactString(v.Error()); changed {
+			return &redactedError{message: redacted, err: v}, true
+		}
+		return value, false
+	}
+
+	rv := reflect.ValueOf(value)
+	switch {
+	case rv.Kind() == reflect.String:
+		// Named string types keep their type
+		if redacted, changed := r.redactString(rv.String()); changed {
+			return reflect.ValueOf(redacted).Convert(rv.Type()).Interface(), true
+		}
+		return value, false
+	case rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8:
+		// Byte slices are matched like strings and keep their type
+		if redacted, changed := r.redactString(string(rv.Bytes())); changed {
+			return reflect.ValueOf([]byte(redacted)).Convert(rv.Type()).Interface(), true
+		}
+		return value, false
+	}
+
+	if depth >= maxRedactionDepth {
+		switch rv.Kind() {
+		case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Struct, reflect.Slice, reflect.Array:
+			// Values nested too deeply to be inspected are not rendered at all
+			return RedactedValue, true
+		}
+		return value, false
+	}
+
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			return value, false
+		}
+		if redacted, changed := r.redactValue(rv.Elem().Interface(), depth+1); changed {
+			return redacted, true
+		}
+		return value, false
+	case reflect.Map:
+		return r.redactMap(rv, depth)
+	case reflect.Struct:
+		return r.redactStruct(rv, depth)
+	case reflect.Slice, reflect.Array:
+		return r.redactSlice(rv, depth)
+	}
+	return value, false
+}
+
+func (r *redaction) redactString(value string) (string, bool) {
+	redacted := value
+	for _, rule := range r.rules {
+		if rule.value != nil {
+			redacted = rule.value.ReplaceAllString(redacted, RedactedValue)
+		}
+	}
+	return redacted, redacted != value
+}
+
+// redactMap redacts maps with string keys, returning a map[string]interface{} if any value has changed
+func (r *redaction) redactMap(rv reflect.Value, depth int) (interface{}, bool) {
+	if rv.Type().Key().Kind() != reflect.String {
+		return rv.Interface(), false
+	}
+
+	result := make(map[string]interface{}, rv.Len())
+	changed := false
+	iter := rv.MapRange()
+	for iter.Next() {
+		key := iter.Key().String()
+		value, valueChanged := r.redactField(key, iter.Value().Interface(), depth+1)
+		result[key] = value
+		changed = changed || valueChanged
+	}
+	if !changed {
+		return rv.Interface(), false
+	}
+	return result, true
+}
+
+// redactStruct redacts the exported fields of structs, keyed by their JSON name,
+// returning a map[string]interface{} if any value has changed.
+// Formatters may render unexported fields, which cannot be inspected: if rules are in effect, unchanged structs
+// with unexported fields are redacted entirely, unless they implement fmt.Stringer, in which case value rules
+// apply to their string representation.
+func (r *redaction) redactStruct(rv reflect.Value, depth int) (interface{}, bool) {
+	rt := rv.Type()
+	result := make(map[string]interface{}, rt.NumField())
+	changed := false
+	unexported := false
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if field.PkgPath != "" {
+			unexported = true
+			continue
+		}
+
+		key := field.Name
+		if tag := field.Tag.Get("json"); tag != "" {
+			name := strings.Split(tag, ",")[0]
+			if name == "-" {
+				continue
+			} else if name != "" {
+				key = name
+			}
+		}
+
+		value, valueChanged := r.redactField(key, rv.Field(i).Interface(), depth+1)
+		result[key] = value
+		changed = changed || valueChanged
+	}
+	if changed {
+		return result, true
+	}
+
+	if unexported && len(r.rules) > 0 {
+		if stringer, ok := rv.Interface().(fmt.Stringer); ok {
+			if redacted, changed := r.redactString(stringer.String()); changed {
+				return redacted, true
+			}
+			return rv.Interface(), false
+		}
+		return RedactedValue, true
+	}
+	return rv.Interface(), false
+}
+
+// redactSlice redacts the elements of slices and arrays, returning a []interface{} if any element has changed
+func (r *redaction) redactSlice(rv reflect.Value, depth int) (interface{}, bool) {
+	result := make([]interface{}, rv.Len())
+	changed := false
+	for i := 0; i < rv.Len(); i++ {
+		value, valueChanged := r.redactValue(rv.Index(i).Interface(), depth+1)
+		result[i] = value
+		changed = changed || valueChanged
+	}
+	if !changed {
+		return rv.Interface(), false
+	}
+	return result, true
+}
@@ -0,0 +1,279 @@
+package modular
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+type redactingValue struct{}
+
+func (redactingValue) Redact() interface{} {
+	return "redacted by value"
+}
+
+type cardNumber string
+
+type card struct {
+	Holder string `json:"holder"`
+	Number string `json:"number"`
+	CVC    string `json:"-"`
+	Note   string
+}
+
+type account struct {
+	Holder string `json:"holder"`
+	number string
+}
+
+type stringerAccount account
+
+func (sa stringerAccount) String() string {
+	return sa.Holder + " " + sa.number
+}
+
+func TestRedactionRule_Constructors(t *testing.T) {
+	_, err := RedactKeyGlob("")
+	require.EqualValues(t, ErrInvalidPattern, err)
+	_, err = RedactKeyGlob("[")
+	require.EqualValues(t, ErrInvalidPattern, err)
+	_, err = RedactValue("(")
+	require.EqualValues(t, ErrInvalidPattern, err)
+	_, err = RedactValue("")
+	require.EqualValues(t, ErrInvalidPattern, err)
+
+	glob, err := RedactKeyGlob("*_Token")
+	require.NoError(t, err)
+	require.EqualValues(t, "glob:*_token", glob.String())
+	require.True(t, glob.matchesKey("access_token"))
+	require.True(t, glob.matchesKey("ACCESS_TOKEN"))
+	require.False(t, glob.matchesKey("token"))
+
+	key := RedactKey("Password")
+	require.EqualValues(t, "key:Password", key.String())
+	require.True(t, key.matchesKey("password"))
+	require.False(t, key.matchesKey("passwords"))
+
+	value, err := RedactValue(`\d+`)
+	require.NoError(t, err)
+	require.EqualValues(t, `value:\d+`, value.String())
+	require.False(t, value.matchesKey("password"))
+}
+
+func TestRedaction_Redact(t *testing.T) {
+	glob, err := RedactKeyGlob("*_token")
+	require.NoError(t, err)
+	r := &redaction{rules: []RedactionRule{RedactKey("password"), glob, RedactCardNumbers, RedactEmails}}
+
+	require.EqualValues(t, RedactedValue, r.redact("password", "hunter2"))
+	require.EqualValues(t, RedactedValue, r.redact("access_token", 42))
+	require.EqualValues(t, "user [REDACTED] paid", r.redact("msg", "user alice@example.com paid"))
+	require.EqualValues(t, "card [REDACTED]", r.redact("msg", "card 4111 1111 1111 1111"))
+	require.EqualValues(t, "card [REDACTED]", r.redact("msg", "card 4111-1111-1111-1111"))
+	require.EqualValues(t, "order 12345", r.redact("msg", "order 12345"))
+	require.EqualValues(t, 42, r.redact("count", 42))
+	require.Nil(t, r.redact("nil", nil))
+	require.EqualValues(t, "redacted by value", r.redact("value", redactingValue{}))
+
+	// Unchanged values are kept as they are
+	unchanged := map[string]int{"a": 1}
+	require.EqualValues(t, unchanged, r.redact("map", unchanged))
+	require.EqualValues(t, card{Holder: "alice"}, r.redact("card", card{Holder: "alice"}))
+
+	// Nested maps, structs, slices and pointers
+	require.EqualValues(t, map[string]interface{}{
+		"user": map[string]interface{}{
+			"password": RedactedValue,
+			"name":     "alice",
+		},
+		"id": 1,
+	}, r.redact("request", map[string]interface{}{
+		"user": map[string]string{
+			"password": "hunter2",
+			"name":     "alice",
+		},
+		"id": 1,
+	}))
+
+	require.EqualValues(t, map[string]interface{}{
+		"holder": "alice",
+		"number": RedactedValue,
+		"Note":   "",
+	}, r.redact("card", &card{Holder: "alice", Number: "4111111111111111", CVC: "123"}))
+
+	require.EqualValues(t, []interface{}{"a", RedactedValue}, r.redact("list", []string{"a", "bob@example.com"}))
+	require.EqualValues(t, []interface{}{"a", "redacted by value"}, r.redact("list", [2]interface{}{"a", redactingValue{}}))
+}
+
+func TestLoggerModule_SetRedactionRules(t *testing.T) {
+	rl := NewRootLogger(logrus.New())
+	payments := rl.GetOrCreateChild("payments", logrus.InfoLevel)
+	card := payments.GetOrCreateChild("card", logrus.InfoLevel)
+	require.Empty(t, card.GetRedactionRules())
+
+	rl.SetRedactionRules(RedactKey("password"))
+	payments.SetRedactionRules(RedactCardNumbers)
+	require.EqualValues(t, []RedactionRule{RedactKey("password"), RedactCardNumbers}, card.GetRedactionRules())
+	require.EqualValues(t, []RedactionRule{RedactKey("password")}, rl.GetRedactionRules())
+
+	card.SetRedactionRules(RedactKey("cvc"))
+	require.EqualValues(t, []RedactionRule{RedactKey("password"), RedactCardNumbers, RedactKey("cvc")}, card.GetRedactionRules())
+}
+
+func TestLoggerBase_Redaction(t *testing.T) {
+	buffer := bytes.NewBufferString("")
+	logger := logrus.New()
+	logger.Out = buffer
+	logger.Formatter = &logrus.JSONFormatter{}
+	rl := NewRootLogger(logger)
+	hook := &recordingHook{}
+	rl.AddHook(hook)
+
+	payments := rl.GetOrCreateChild("payments", logrus.InfoLevel)
+	payments.SetRedactionRules(RedactCardNumbers)
+	card := payments.GetOrCreateChild("card", logrus.InfoLevel)
+	other := rl.GetOrCreateChild("other", logrus.InfoLevel)
+
+	fields := logrus.Fields{"number": "4111111111111111"}
+	card.WithFields(fields).Info("test")
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(buffer.Bytes(), &entry))
+	require.EqualValues(t, RedactedValue, entry["number"])
+	require.Len(t, hook.entries, 1)
+	require.EqualValues(t, RedactedValue, hook.entries[0].Data["number"])
+	// The passed fields are left untouched
+	require.EqualValues(t, "4111111111111111", fields["number"])
+
+	// Modules outside of the subtree are not affected
+	buffer.Reset()
+	other.WithFields(fields).Info("test")
+	require.NoError(t, json.Unmarshal(buffer.Bytes(), &entry))
+	require.EqualValues(t, "4111111111111111", entry["number"])
+}
+
+func TestRedaction_Redact_Pointers(t *testing.T) {
+	r := &redaction{rules: []RedactionRule{RedactKey("password")}}
+
+	// Unchanged pointers and errors are kept as they are
+	value := &card{Holder: "alice"}
+	require.True(t, value == r.redact("card", value))
+	err := errors.New("failed")
+	require.EqualValues(t, err, r.redact("error", err))
+}
+
+func TestRedaction_Redact_Unexported(t *testing.T) {
+	r := &redaction{rules: []RedactionRule{RedactKey("password"), RedactCardNumbers}}
+
+	// Unexported fields cannot be inspected, structs holding them are redacted entirely
+	require.EqualValues(t, RedactedValue, r.redact("account", account{Holder: "alice", number: "4111111111111111"}))
+	require.EqualValues(t, RedactedValue, r.redact("account", &account{Holder: "alice"}))
+	require.EqualValues(t, map[string]interface{}{"holder": "alice", "password": RedactedValue},
+		r.redact("account", struct {
+			Holder   string `json:"holder"`
+			Password string `json:"password"`
+			number   string
+		}{Holder: "alice", Password: "hunter2", number: "4111111111111111"}))
+
+	// Unless they are rendered through String
+	require.EqualValues(t, "alice "+RedactedValue, r.redact("account", stringerAccount{Holder: "alice", number: "4111111111111111"}))
+	require.EqualValues(t, stringerAccount{Holder: "alice"}, r.redact("account", stringerAccount{Holder: "alice"}))
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	require.EqualValues(t, start, r.redact("start", start))
+
+	// Without rules, structs are kept as they are
+	r = &redaction{}
+	require.EqualValues(t, account{Holder: "alice", number: "4111111111111111"},
+		r.redact("account", account{Holder: "alice", number: "4111111111111111"}))
+}
+
+func TestRedaction_Redact_Depth(t *testing.T) {
+	r := &redaction{rules: []RedactionRule{RedactKey("password")}}
+
+	// Values nested deeper than can be inspected are redacted
+	var value interface{} = map[string]interface{}{"password": "hunter2"}
+	for i := 0; i < maxRedactionDepth; i++ {
+		value = map[string]interface{}{"nested": value}
+	}
+	redacted := r.redact("request", value)
+	for i := 0; i < maxRedactionDepth; i++ {
+		require.IsType(t, map[string]interface{}{}, redacted)
+		redacted = redacted.(map[string]interface{})["nested"]
+	}
+	require.EqualValues(t, RedactedValue, redacted)
+
+	// Primitive values at the limit are kept
+	value = "hunter2"
+	for i := 0; i < maxRedactionDepth; i++ {
+		value = []interface{}{value}
+	}
+	require.EqualValues(t, value, r.redact("list", value))
+}
+
+func TestRedaction_Redact_NamedTypes(t *testing.T) {
+	rule, err := RedactValue(`\d{16}`)
+	require.NoError(t, err)
+	r := &redaction{rules: []RedactionRule{rule}}
+
+	// Named string types and byte slices are redacted and keep their type
+	require.EqualValues(t, cardNumber(RedactedValue), r.redact("card", cardNumber("4111111111111111")))
+	require.EqualValues(t, []byte("card "+RedactedValue), r.redact("raw", []byte("card 4111111111111111")))
+	require.EqualValues(t, json.RawMessage(`"`+RedactedValue+`"`), r.redact("raw", json.RawMessage(`"4111111111111111"`)))
+	require.EqualValues(t, map[string]interface{}{"number": cardNumber(RedactedValue)},
+		r.redact("card", map[string]cardNumber{"number": "4111111111111111"}))
+
+	// Values without matches are kept as they are
+	require.EqualValues(t, cardNumber("n/a"), r.redact("card", cardNumber("n/a")))
+	require.EqualValues(t, []byte("n/a"), r.redact("raw", []byte("n/a")))
+}
+
+func TestLoggerBase_Redaction_Message(t *testing.T) {
+	buffer := bytes.NewBufferString("")
+	logger := logrus.New()
+	logger.Out = buffer
+	logger.Formatter = &logrus.TextFormatter{DisableTimestamp: true}
+	rl := NewRootLogger(logger)
+	rl.SetRedactionRules(RedactCardNumbers)
+
+	// Value rules apply to messages and to structs with unexported fields rendered by formatters
+	rl.Infof("charging card %s", "4111111111111111")
+	rl.WithField("account", account{Holder: "alice", number: "4111111111111111"}).Info("charged")
+	require.NotContains(t, buffer.String(), "4111111111111111")
+	require.Contains(t, buffer.String(), `msg="charging card [REDACTED]"`)
+	require.Contains(t, buffer.String(), `account="[REDACTED]"`)
+}
+
+func TestLoggerBase_Redaction_WithError(t *testing.T) {
+	buffer := bytes.NewBufferString("")
+	logger := logrus.New()
+	logger.Out = buffer
+	logger.Formatter = &logrus.JSONFormatter{}
+	rl := NewRootLogger(logger)
+
+	rl.WithError(errors.New("boom")).Error("test")
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(buffer.Bytes(), &entry))
+	require.EqualValues(t, "boom", entry[logrus.ErrorKey])
+
+	// Value rules apply to the message, hooks still receive an error
+	hook := &recordingHook{}
+	rl.AddHook(hook)
+	rl.SetRedactionRules(RedactCardNumbers)
+	buffer.Reset()
+	declined := errors.New("card 4111111111111111 declined")
+	rl.WithError(declined).Error("test")
+	entry = nil
+	require.NoError(t, json.Unmarshal(buffer.Bytes(), &entry))
+	require.EqualValues(t, "card "+RedactedValue+" declined", entry[logrus.ErrorKey])
+
+	require.Len(t, hook.entries, 1)
+	err, ok := hook.entries[0].Data[logrus.ErrorKey].(error)
+	require.True(t, ok)
+	require.EqualValues(t, "card "+RedactedValue+" declined", err.Error())
+	require.True(t, errors.Is(err, declined))
+}
@@ -0,0 +1,170 @@
+package modular
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Registry maps names to independent root loggers, so modules of all mounted trees can be resolved
+// by prefixed names: "tenantA.db" resolves to the module "db" of the root logger mounted at "tenantA".
+// Mount prefixes are separated from module names by DefaultSeparator, module names are then
+// resolved using the separator of the mounted root logger.
+type Registry struct {
+	mountsMutex sync.Mutex
+	mounts      map[string]RootLogger
+}
+
+// NewRegistry creates a new, empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		mounts: make(map[string]RootLogger),
+	}
+}
+
+// Mount mounts the root logger at the given prefix
+func (r *Registry) Mount(prefix string, root RootLogger) error {
+	if root == nil {
+		return ErrNilRoot
+	}
+	if _, err := splitModuleName(prefix, DefaultSeparator); err != nil {
+		return err
+	}
+
+	r.mountsMutex.Lock()
+	defer r.mountsMutex.Unlock()
+
+	if _, ok := r.mounts[prefix]; ok {
+		return ErrMountExists
+	}
+	r.mounts[prefix] = root
+	return nil
+}
+
+// Unmount removes the root logger mounted at the given prefix
+func (r *Registry) Unmount(prefix string) error {
+	r.mountsMutex.Lock()
+	defer r.mountsMutex.Unlock()
+
+	if _, ok := r.mounts[prefix]; !ok {
+		return ErrMountNotFound
+	}
+	delete(r.mounts, prefix)
+	return nil
+}
+
+// Get returns the root logger mounted at the given prefix
+func (r *Registry) Get(prefix string) (RootLogger, bool) {
+	r.mountsMutex.Lock()
+	defer r.mountsMutex.Unlock()
+
+	root, ok := r.mounts[prefix]
+	return root, ok
+}
+
+// Prefixes returns the prefixes of all mounted root loggers, ordered by name
+func (r *Registry) Prefixes() []string {
+	r.mountsMutex.Lock()
+	defer r.mountsMutex.Unlock()
+
+	prefixes := make([]string, 0, len(r.mounts))
+	for prefix := range r.mounts {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Strings(prefixes)
+	return prefixes
+}
+
+// resolve returns the root logger mounted at the longest prefix of name and the remaining module name
+func (r *Registry) resolve(name string) (root RootLogger, moduleName string, ok bool) {
+	r.mountsMutex.Lock()
+	defer r.mountsMutex.Unlock()
+
+	matchedPrefix := ""
+	for prefix, mountedRoot := range r.mounts {
+		if len(prefix) <= len(matchedPrefix) {
+			continue
+		}
+		if name == prefix {
+			root, moduleName, ok = mountedRoot, "", true
+			matchedPrefix = prefix
+		} else if strings.HasPrefix(name, prefix+DefaultSeparator) {
+			root, moduleName, ok = mountedRoot, name[len(prefix)+len(DefaultSeparator):], true
+			matchedPrefix = prefix
+		}
+	}
+	return
+}
+
+// Lookup returns the module with the given prefixed name, i.e. "tenantA.db".
+// The prefix alone resolves to the mounted root logger.
+func (r *Registry) Lookup(name string) (ModuleLogger, error) {
+	root, moduleName, ok := r.resolve(name)
+	if !ok {
+		return nil, &ChildNotFoundError{Module: name}
+	}
+	if moduleName == "" {
+		return root, nil
+	}
+	return root.GetChild(moduleName)
+}
+
+// ApplyLevelSpec applies the level spec across all mounted trees.
+// Entries are resolved like names passed to Lookup, missing modules are created.
+// Entries without a module name apply to all mounted root loggers.
+// Pattern entries such as "*.sql=debug" are added as level rules to all mounted root loggers,
+// matching the prefixed names of their modules. As with LevelSpec.Apply, the last matching entry
+// determines the level of existing modules as well as of modules created later on.
+func (r *Registry) ApplyLevelSpec(spec LevelSpec) error {
+	for _, entry := range spec {
+		if entry.Module == "" || isLevelPattern(entry.Module) {
+			for _, prefix := range r.Prefixes() {
+				if root, ok := r.Get(prefix); ok {
+					if err := applyLevelSpecEntry(root, entry, prefix); err != nil {
+						return err
+					}
+				}
+			}
+			continue
+		}
+
+		root, moduleName, ok := r.resolve(entry.Module)
+		if !ok {
+			return &LevelSpecError{Entry: entry.Module, Err: ErrMountNotFound}
+		}
+		if err := applyLevelSpecEntry(root, LevelSpecEntry{Module: moduleName, Level: entry.Level}, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Walk calls fn for every module of all mounted trees, including the root loggers,
+// passing the prefixed module name. Trees are walked depth-first, ordered by name.
+func (r *Registry) Walk(fn func(name string, module ModuleLogger)) {
+	for _, prefix := range r.Prefixes() {
+		root, ok := r.Get(prefix)
+		if !ok {
+			continue
+		}
+		walkModules(root, func(module ModuleLogger) {
+			fn(prefixedModuleName(prefix, module.GetModuleName()), module)
+		})
+	}
+}
+
+// prefixedModuleName returns the name of a module of the root logger mounted at the given prefix
+func prefixedModuleName(prefix, moduleName string) string {
+	if moduleName == "" {
+		return prefix
+	}
+	return prefix + DefaultSeparator + moduleName
+}
+
+// walkModules calls fn for the module and all of its descendants, depth-first and ordered by name
+func walkModules(module ModuleLogger, fn func(module ModuleLogger)) {
+	fn(module)
+	for _, child := range module.GetChildren() {
+		walkModules(child, fn)
+	}
+}
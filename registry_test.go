@@ -0,0 +1,149 @@
+package modular
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_Mount(t *testing.T) {
+	r := NewRegistry()
+	tenantA := NewRootLogger(logrus.New())
+	tenantB := NewRootLogger(logrus.New())
+
+	require.NoError(t, r.Mount("tenantA", tenantA))
+	require.NoError(t, r.Mount("tenants.b", tenantB))
+	require.EqualError(t, r.Mount("tenantA", tenantB), ErrMountExists.Error())
+	require.EqualError(t, r.Mount("tenantC", nil), ErrNilRoot.Error())
+	require.True(t, errors.Is(r.Mount("tenant..C", tenantB), ErrInvalidModuleName))
+
+	require.EqualValues(t, []string{"tenantA", "tenants.b"}, r.Prefixes())
+
+	root, ok := r.Get("tenantA")
+	require.True(t, ok)
+	require.EqualValues(t, tenantA, root)
+
+	require.NoError(t, r.Unmount("tenantA"))
+	require.EqualError(t, r.Unmount("tenantA"), ErrMountNotFound.Error())
+	_, ok = r.Get("tenantA")
+	require.False(t, ok)
+}
+
+func TestRegistry_Lookup(t *testing.T) {
+	r := NewRegistry()
+	tenantA := NewRootLogger(logrus.New())
+	tenantADB := tenantA.GetOrCreateChild("db", logrus.InfoLevel)
+	nested := NewRootLogger(logrus.New())
+	require.NoError(t, nested.SetSeparator("/"))
+	nestedModule := nested.GetOrCreateChild("github.com/acme", logrus.InfoLevel)
+
+	require.NoError(t, r.Mount("tenantA", tenantA))
+	require.NoError(t, r.Mount("tenantA.nested", nested))
+
+	module, err := r.Lookup("tenantA.db")
+	require.NoError(t, err)
+	require.EqualValues(t, tenantADB, module)
+
+	module, err = r.Lookup("tenantA")
+	require.NoError(t, err)
+	require.EqualValues(t, tenantA, module)
+
+	// Longest prefix wins, the remainder is resolved using the root's separator
+	module, err = r.Lookup("tenantA.nested.github.com/acme")
+	require.NoError(t, err)
+	require.EqualValues(t, nestedModule, module)
+
+	_, err = r.Lookup("tenantA.missing")
+	require.True(t, errors.Is(err, ErrChildNotFound))
+
+	_, err = r.Lookup("tenantB.db")
+	require.True(t, errors.Is(err, ErrChildNotFound))
+	require.EqualError(t, err, "Child logger not found: tenantB.db")
+}
+
+func TestRegistry_ApplyLevelSpec(t *testing.T) {
+	r := NewRegistry()
+	tenantA := NewRootLogger(logrus.New())
+	tenantB := NewRootLogger(logrus.New())
+	require.NoError(t, r.Mount("tenantA", tenantA))
+	require.NoError(t, r.Mount("tenantB", tenantB))
+
+	spec, err := ParseLevelSpec("warn,tenantA.db=debug,tenantB=error")
+	require.NoError(t, err)
+	require.NoError(t, r.ApplyLevelSpec(spec))
+
+	require.EqualValues(t, logrus.WarnLevel, tenantA.GetLevel())
+	require.EqualValues(t, logrus.ErrorLevel, tenantB.GetLevel())
+	db, err := tenantA.GetChild("db")
+	require.NoError(t, err)
+	require.EqualValues(t, logrus.DebugLevel, db.GetLevel())
+
+	spec, err = ParseLevelSpec("tenantC.db=debug")
+	require.NoError(t, err)
+	err = r.ApplyLevelSpec(spec)
+	require.True(t, errors.Is(err, ErrInvalidLevelSpec))
+	require.True(t, errors.Is(err, ErrMountNotFound))
+}
+
+func TestRegistry_ApplyLevelSpec_Patterns(t *testing.T) {
+	r := NewRegistry()
+	tenantA := NewRootLogger(logrus.New())
+	sqlA := tenantA.GetOrCreateChild("db.sql", logrus.InfoLevel)
+	cacheA := tenantA.GetOrCreateChild("cache", logrus.InfoLevel)
+	tenantB := NewRootLogger(logrus.New())
+	sqlB := tenantB.GetOrCreateChild("db.sql", logrus.InfoLevel)
+	require.NoError(t, r.Mount("tenantA", tenantA))
+	require.NoError(t, r.Mount("tenantB", tenantB))
+
+	// Patterns match the prefixed names across all mounted trees
+	spec, err := ParseLevelSpec("*.db.sql=debug,**.cache=error")
+	require.NoError(t, err)
+	require.NoError(t, r.ApplyLevelSpec(spec))
+	require.EqualValues(t, logrus.DebugLevel, sqlA.GetLevel())
+	require.EqualValues(t, logrus.DebugLevel, sqlB.GetLevel())
+	require.EqualValues(t, logrus.ErrorLevel, cacheA.GetLevel())
+	require.EqualValues(t, logrus.InfoLevel, tenantA.GetLevel())
+
+	// Modules created later on are affected as well, the last matching entry wins
+	cacheB := tenantB.GetOrCreateChild("cache", logrus.InfoLevel)
+	require.EqualValues(t, logrus.ErrorLevel, cacheB.GetLevel())
+	require.EqualValues(t, logrus.InfoLevel, tenantB.GetOrCreateChild("other", logrus.InfoLevel).GetLevel())
+
+	spec, err = ParseLevelSpec("tenantA.db.sql=warn,*.*.sql=trace")
+	require.NoError(t, err)
+	require.NoError(t, r.ApplyLevelSpec(spec))
+	require.EqualValues(t, logrus.TraceLevel, sqlA.GetLevel())
+	require.NoError(t, tenantA.RemoveChild("db"))
+	require.EqualValues(t, logrus.TraceLevel, tenantA.GetOrCreateChild("db.sql", logrus.InfoLevel).GetLevel())
+
+	spec, err = ParseLevelSpec("*.*.sql=trace,tenantA.db.sql=warn")
+	require.NoError(t, err)
+	require.NoError(t, r.ApplyLevelSpec(spec))
+	require.NoError(t, tenantA.RemoveChild("db"))
+	require.EqualValues(t, logrus.WarnLevel, tenantA.GetOrCreateChild("db.sql", logrus.InfoLevel).GetLevel())
+	require.EqualValues(t, logrus.TraceLevel, sqlB.GetLevel())
+
+	spec, err = ParseLevelSpec("re:(=debug")
+	require.NoError(t, err)
+	err = r.ApplyLevelSpec(spec)
+	require.True(t, errors.Is(err, ErrInvalidLevelSpec))
+	require.True(t, errors.Is(err, ErrInvalidPattern))
+}
+
+func TestRegistry_Walk(t *testing.T) {
+	r := NewRegistry()
+	tenantA := NewRootLogger(logrus.New())
+	tenantA.GetOrCreateChild("db.sql", logrus.InfoLevel)
+	tenantA.GetOrCreateChild("cache", logrus.InfoLevel)
+	tenantB := NewRootLogger(logrus.New())
+	require.NoError(t, r.Mount("tenantB", tenantB))
+	require.NoError(t, r.Mount("tenantA", tenantA))
+
+	var names []string
+	r.Walk(func(name string, module ModuleLogger) {
+		names = append(names, name)
+	})
+	require.EqualValues(t, []string{"tenantA", "tenantA.cache", "tenantA.db", "tenantA.db.sql", "tenantB"}, names)
+}
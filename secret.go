@@ -0,0 +1,139 @@
+package modular
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// HashedPrefix defines the prefix of rendered Hashed values
+const HashedPrefix = "hash:"
+
+// hashedBytes defines how many bytes of the HMAC are rendered
+const hashedBytes = 8
+
+var (
+	defaultHashSaltOnce sync.Once
+	defaultHashSalt     []byte
+)
+
+// SecretValue wraps a sensitive value which is rendered as RedactedValue, see Secret
+type SecretValue struct {
+	value interface{}
+}
+
+// Secret wraps a sensitive value. Passed as a field, the value is rendered as RedactedValue
+// unless the module is configured to reveal secrets through SetRevealSecrets.
+func Secret(value interface{}) SecretValue {
+	return SecretValue{value: value}
+}
+
+// Value returns the wrapped value
+func (sv SecretValue) Value() interface{} {
+	return sv.value
+}
+
+// String returns RedactedValue, so the value is not revealed when formatted outside of a module
+func (sv SecretValue) String() string {
+	return RedactedValue
+}
+
+// GoString returns RedactedValue, so the value is not revealed by %#v
+func (sv SecretValue) GoString() string {
+	return RedactedValue
+}
+
+// MarshalJSON encodes RedactedValue, so the value is not revealed when encoded outside of a module
+func (sv SecretValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(RedactedValue)
+}
+
+// HashedValue wraps a sensitive value which is rendered as salted hash, see Hashed
+type HashedValue struct {
+	value interface{}
+}
+
+// Hashed wraps a sensitive value. Passed as a field, the value is rendered as HashedPrefix followed by
+// a truncated HMAC-SHA256 of the value's default format, keyed with the root's hash salt,
+// unless the module is configured to reveal secrets through SetRevealSecrets.
+// Equal values render equally for the same salt, so entries can be correlated without revealing the value.
+func Hashed(value interface{}) HashedValue {
+	return HashedValue{value: value}
+}
+
+// Value returns the wrapped value
+func (hv HashedValue) Value() interface{} {
+	return hv.value
+}
+
+// String returns RedactedValue, as the root's hash salt is not known outside of a module
+func (hv HashedValue) String() string {
+	return RedactedValue
+}
+
+// GoString returns RedactedValue, so the value is not revealed by %#v
+func (hv HashedValue) GoString() string {
+	return RedactedValue
+}
+
+// MarshalJSON encodes RedactedValue, as the root's hash salt is not known outside of a module
+func (hv HashedValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(RedactedValue)
+}
+
+// hash returns the rendered hash of the value, keyed with the given salt
+func (hv HashedValue) hash(salt []byte) string {
+	mac := hmac.New(sha256.New, salt)
+	fmt.Fprint(mac, hv.value)
+	return HashedPrefix + hex.EncodeToString(mac.Sum(nil)[:hashedBytes])
+}
+
+// getDefaultHashSalt returns the random salt used by roots which do not configure one
+func getDefaultHashSalt() []byte {
+	defaultHashSaltOnce.Do(func() {
+		defaultHashSalt = make([]byte, sha256.Size)
+		if _, err := rand.Read(defaultHashSalt); err != nil {
+			panic(err)
+		}
+	})
+	return defaultHashSalt
+}
+
+func (lm *loggerModule) SetRevealSecrets(enabled bool) {
+	lm.settingsMutex.Lock()
+	defer lm.settingsMutex.Unlock()
+	lm.revealSecrets = &enabled
+	invalidateSettings()
+}
+
+func (lm *loggerModule) GetRevealSecrets() bool {
+	return lm.resolveSettings().revealSecrets
+}
+
+func (lr *loggerRoot) SetHashSalt(salt []byte) {
+	lr.hashSaltMutex.Lock()
+	defer lr.hashSaltMutex.Unlock()
+	lr.hashSalt = append([]byte(nil), salt...)
+}
+
+// getHashSalt returns the configured salt, falling back to a random salt generated once per process
+func (lr *loggerRoot) getHashSalt() []byte {
+	lr.hashSaltMutex.Lock()
+	defer lr.hashSaltMutex.Unlock()
+	if len(lr.hashSalt) == 0 {
+		return getDefaultHashSalt()
+	}
+	return lr.hashSalt
+}
+
+// hashSaltOf returns the hash salt of the given root
+func hashSaltOf(root RootLogger) []byte {
+	if lr, ok := root.(interface{ getHashSalt() []byte }); ok {
+		return lr.getHashSalt()
+	}
+	return getDefaultHashSalt()
+}
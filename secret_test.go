@@ -0,0 +1,109 @@
+package modular
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecret(t *testing.T) {
+	secret := Secret("hunter2")
+	require.EqualValues(t, "hunter2", secret.Value())
+	require.EqualValues(t, RedactedValue, fmt.Sprint(secret))
+	require.EqualValues(t, "[REDACTED] [REDACTED] [REDACTED]", fmt.Sprintf("%v %s %#v", secret, secret, secret))
+
+	encoded, err := json.Marshal(map[string]interface{}{"password": secret})
+	require.NoError(t, err)
+	require.EqualValues(t, `{"password":"[REDACTED]"}`, string(encoded))
+}
+
+func TestHashed(t *testing.T) {
+	hashed := Hashed("alice@example.com")
+	require.EqualValues(t, "alice@example.com", hashed.Value())
+	require.EqualValues(t, RedactedValue, fmt.Sprint(hashed))
+	require.NotContains(t, fmt.Sprintf("%#v", hashed), "alice")
+
+	encoded, err := json.Marshal(hashed)
+	require.NoError(t, err)
+	require.EqualValues(t, `"[REDACTED]"`, string(encoded))
+
+	hash := hashed.hash([]byte("salt"))
+	require.True(t, strings.HasPrefix(hash, HashedPrefix))
+	require.Len(t, hash, len(HashedPrefix)+2*hashedBytes)
+	require.EqualValues(t, hash, Hashed("alice@example.com").hash([]byte("salt")))
+	require.NotEqual(t, hash, Hashed("bob@example.com").hash([]byte("salt")))
+	require.NotEqual(t, hash, hashed.hash([]byte("pepper")))
+}
+
+func TestLoggerModule_SetRevealSecrets(t *testing.T) {
+	rl := NewRootLogger(logrus.New())
+	child := rl.GetOrCreateChild("child", logrus.InfoLevel)
+	require.False(t, child.GetRevealSecrets())
+
+	rl.SetRevealSecrets(true)
+	require.True(t, child.GetRevealSecrets())
+
+	child.SetRevealSecrets(false)
+	require.False(t, child.GetRevealSecrets())
+	require.True(t, rl.GetRevealSecrets())
+}
+
+func TestLoggerRoot_SetHashSalt(t *testing.T) {
+	rl := NewRootLogger(logrus.New())
+	require.EqualValues(t, getDefaultHashSalt(), hashSaltOf(rl))
+	require.Len(t, getDefaultHashSalt(), 32)
+
+	salt := []byte("salt")
+	rl.SetHashSalt(salt)
+	salt[0] = 'x'
+	require.EqualValues(t, []byte("salt"), hashSaltOf(rl))
+
+	rl.SetHashSalt(nil)
+	require.EqualValues(t, getDefaultHashSalt(), hashSaltOf(rl))
+	require.EqualValues(t, getDefaultHashSalt(), hashSaltOf(nil))
+}
+
+func TestLoggerBase_SecretFields(t *testing.T) {
+	buffer := bytes.NewBufferString("")
+	logger := logrus.New()
+	logger.Out = buffer
+	logger.Formatter = &logrus.JSONFormatter{}
+	rl := NewRootLogger(logger)
+	rl.SetHashSalt([]byte("salt"))
+	rl.SetRedactionRules(RedactEmails)
+
+	users := rl.GetOrCreateChild("users", logrus.InfoLevel)
+	debug := rl.GetOrCreateChild("debug", logrus.InfoLevel)
+	debug.SetRevealSecrets(true)
+
+	fields := logrus.Fields{
+		"password": Secret("hunter2"),
+		"email":    Hashed("alice@example.com"),
+		"nested":   map[string]interface{}{"token": Secret("abc")},
+	}
+
+	read := func() map[string]interface{} {
+		var entry map[string]interface{}
+		require.NoError(t, json.Unmarshal(buffer.Bytes(), &entry))
+		buffer.Reset()
+		return entry
+	}
+
+	users.WithFields(fields).Info("test")
+	entry := read()
+	require.EqualValues(t, RedactedValue, entry["password"])
+	require.EqualValues(t, Hashed("alice@example.com").hash([]byte("salt")), entry["email"])
+	require.EqualValues(t, map[string]interface{}{"token": RedactedValue}, entry["nested"])
+
+	// Revealed values are still subject to redaction rules
+	debug.WithFields(fields).Info("test")
+	entry = read()
+	require.EqualValues(t, "hunter2", entry["password"])
+	require.EqualValues(t, RedactedValue, entry["email"])
+	require.EqualValues(t, map[string]interface{}{"token": "abc"}, entry["nested"])
+}
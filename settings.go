@@ -0,0 +1,110 @@
+package modular
+
+import (
+	"sync/atomic"
+)
+
+// settingsGeneration is incremented whenever an inherited setting of any module changes,
+// invalidating the resolved settings of all modules
+var settingsGeneration uint64
+
+// resolvedSettings holds the inherited settings of a module, resolved against its ancestors.
+// Resolved settings are shared and must not be modified.
+type resolvedSettings struct {
+	generation       uint64
+	reportCaller     bool
+	fatalPolicy      FatalPolicy
+	recorderSize     int
+	redactionRules   []RedactionRule
+	revealSecrets    bool
+	errorExpansion   ErrorExpansion
+	stackTracePolicy StackTracePolicy
+}
+
+// invalidateSettings marks the resolved settings of all modules as outdated, must be called after changing a setting
+func invalidateSettings() {
+	atomic.AddUint64(&settingsGeneration, 1)
+}
+
+// resolveSettings returns the module's settings, resolving them against its ancestors only if a setting
+// has changed since they were last resolved
+func (lm *loggerModule) resolveSettings() *resolvedSettings {
+	generation := atomic.LoadUint64(&settingsGeneration)
+	if settings, ok := lm.resolved.Load().(*resolvedSettings); ok && settings.generation == generation {
+		return settings
+	}
+
+	var (
+		reportCaller     *bool
+		fatalPolicy      *FatalPolicy
+		recorderSize     int
+		redactionRules   []RedactionRule
+		revealSecrets    *bool
+		errorExpansion   *ErrorExpansion
+		stackTracePolicy *StackTracePolicy
+	)
+	for module := lm; module != nil; module = module.parent {
+		module.settingsMutex.Lock()
+		if reportCaller == nil {
+			reportCaller = module.reportCaller
+		}
+		if fatalPolicy == nil {
+			fatalPolicy = module.fatalPolicy
+		}
+		if recorderSize == 0 {
+			recorderSize = module.recorderSize
+		}
+		if len(module.redactionRules) > 0 {
+			redactionRules = append(append([]RedactionRule(nil), module.redactionRules...), redactionRules...)
+		}
+		if revealSecrets == nil {
+			revealSecrets = module.revealSecrets
+		}
+		if errorExpansion == nil {
+			errorExpansion = module.errorExpansion
+		}
+		if stackTracePolicy == nil {
+			stackTracePolicy = module.stackTracePolicy
+		}
+		module.settingsMutex.Unlock()
+	}
+
+	settings := &resolvedSettings{
+		generation:     generation,
+		recorderSize:   recorderSize,
+		redactionRules: redactionRules,
+	}
+	if reportCaller != nil {
+		settings.reportCaller = *reportCaller
+	}
+	if fatalPolicy != nil {
+		settings.fatalPolicy = *fatalPolicy
+	}
+	if revealSecrets != nil {
+		settings.revealSecrets = *revealSecrets
+	}
+	if errorExpansion != nil {
+		settings.errorExpansion = *errorExpansion
+	}
+	if stackTracePolicy != nil {
+		settings.stackTracePolicy = *stackTracePolicy
+	}
+
+	lm.resolved.Store(settings)
+	return settings
+}
+
+// settingsOf returns the resolved settings of the given module
+func settingsOf(moduleLogger ModuleLogger) *resolvedSettings {
+	if lm, ok := moduleLogger.(interface{ resolveSettings() *resolvedSettings }); ok {
+		return lm.resolveSettings()
+	}
+	return &resolvedSettings{
+		reportCaller:     moduleLogger.GetReportCaller(),
+		fatalPolicy:      moduleLogger.GetFatalPolicy(),
+		redactionRules:   moduleLogger.GetRedactionRules(),
+		revealSecrets:    moduleLogger.GetRevealSecrets(),
+		errorExpansion:   moduleLogger.GetErrorExpansion(),
+		stackTracePolicy: moduleLogger.GetStackTracePolicy(),
+	}
+}
@@ -0,0 +1,34 @@
+package modular
+
+import (
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerModule_ResolveSettings(t *testing.T) {
+	rl, err := New(logrus.New())
+	require.NoError(t, err)
+	module := rl.GetOrCreateChild("db", logrus.InfoLevel)
+	child := module.GetOrCreateChild("sql", logrus.InfoLevel)
+
+	// Settings are resolved once and shared until a setting changes
+	settings := settingsOf(child)
+	require.False(t, settings.reportCaller)
+	require.True(t, settings == settingsOf(child))
+
+	// Changes of ancestors are picked up by children
+	rl.SetReportCaller(true)
+	require.False(t, settings == settingsOf(child))
+	require.True(t, settingsOf(child).reportCaller)
+
+	module.SetRevealSecrets(true)
+	require.True(t, settingsOf(child).revealSecrets)
+	require.False(t, settingsOf(rl).revealSecrets)
+
+	// Settings of the child take precedence over inherited ones
+	child.SetReportCaller(false)
+	require.False(t, settingsOf(child).reportCaller)
+	require.True(t, settingsOf(module).reportCaller)
+}
@@ -0,0 +1,111 @@
+package modular
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// StackTraceField defines the field holding stack traces attached according to the StackTracePolicy
+	StackTraceField = "stack"
+	// DefaultStackTraceDepth defines the maximum number of frames of a stack trace if none is configured
+	DefaultStackTraceDepth = 32
+)
+
+// stackTraceSlack defines how many frames are captured in addition to the maximum depth
+const stackTraceSlack = 8
+
+// StackTraceFormat defines how attached stack traces are formatted
+type StackTraceFormat int
+
+const (
+	// StackTraceFormatFull formats one function per line, followed by its indented file and line,
+	// as in goroutine dumps
+	StackTraceFormatFull StackTraceFormat = iota
+	// StackTraceFormatCompact formats all frames on a single line, i.e. "db.(*Conn).Query conn.go:42; main.main main.go:12"
+	StackTraceFormatCompact
+)
+
+// StackTracePolicy defines whether the stack trace of the goroutine is attached to entries.
+// Stack traces start at the call to the logging method, leaving out the frames of this package.
+type StackTracePolicy struct {
+	// Enabled enables attaching stack traces
+	Enabled bool
+	// Level defines the threshold, stack traces are attached to entries at this level or more severe
+	Level logrus.Level
+	// MaxDepth limits the number of frames, DefaultStackTraceDepth is used if not positive.
+	// Truncated stack traces end with "...".
+	MaxDepth int
+	// Format defines how the stack trace is formatted
+	Format StackTraceFormat
+}
+
+// appliesTo reports whether a stack trace is attached to entries of the given level
+func (sp StackTracePolicy) appliesTo(level logrus.Level) bool {
+	return sp.Enabled && level <= sp.Level
+}
+
+func (sp StackTracePolicy) maxDepth() int {
+	if sp.MaxDepth <= 0 {
+		return DefaultStackTraceDepth
+	}
+	return sp.MaxDepth
+}
+
+func (lm *loggerModule) SetStackTracePolicy(policy StackTracePolicy) {
+	lm.settingsMutex.Lock()
+	defer lm.settingsMutex.Unlock()
+	lm.stackTracePolicy = &policy
+	invalidateSettings()
+}
+
+func (lm *loggerModule) GetStackTracePolicy() StackTracePolicy {
+	return lm.resolveSettings().stackTracePolicy
+}
+
+// captureStack returns the formatted stack trace of the goroutine, skipping the given number of frames
+// above the caller of captureStack. Frames of the runtime package are left out.
+func captureStack(skip int, policy StackTracePolicy) string {
+	maxDepth := policy.maxDepth()
+	// Capture additional frames to leave room for runtime frames and to detect truncation
+	pcs := make([]uintptr, maxDepth+stackTraceSlack)
+	n := runtime.Callers(skip+2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	var lines []string
+	truncated := n == len(pcs)
+	for more := n > 0; more; {
+		var frame runtime.Frame
+		frame, more = frames.Next()
+		if strings.HasPrefix(frame.Function, "runtime.") {
+			continue
+		}
+		if len(lines) == maxDepth {
+			truncated = true
+			break
+		}
+		lines = append(lines, formatFrame(frame, policy.Format))
+	}
+	if truncated {
+		lines = append(lines, "...")
+	}
+
+	if policy.Format == StackTraceFormatCompact {
+		return strings.Join(lines, "; ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatFrame formats a single frame
+func formatFrame(frame runtime.Frame, format StackTraceFormat) string {
+	function := unescapeFuncName(frame.Function)
+	if format == StackTraceFormatCompact {
+		return fmt.Sprintf("%s %s:%d", path.Base(function), filepath.Base(frame.File), frame.Line)
+	}
+	return fmt.Sprintf("%s\n\t%s:%d", function, frame.File, frame.Line)
+}
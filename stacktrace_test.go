@@ -0,0 +1,97 @@
+package modular
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerModule_SetStackTracePolicy(t *testing.T) {
+	rl := NewRootLogger(logrus.New())
+	core := rl.GetOrCreateChild("core", logrus.InfoLevel)
+	child := core.GetOrCreateChild("child", logrus.InfoLevel)
+	require.EqualValues(t, StackTracePolicy{}, child.GetStackTracePolicy())
+
+	policy := StackTracePolicy{Enabled: true, Level: logrus.ErrorLevel}
+	core.SetStackTracePolicy(policy)
+	require.EqualValues(t, policy, child.GetStackTracePolicy())
+	require.EqualValues(t, StackTracePolicy{}, rl.GetStackTracePolicy())
+
+	child.SetStackTracePolicy(StackTracePolicy{})
+	require.EqualValues(t, StackTracePolicy{}, child.GetStackTracePolicy())
+}
+
+func TestStackTracePolicy_AppliesTo(t *testing.T) {
+	require.False(t, StackTracePolicy{Level: logrus.ErrorLevel}.appliesTo(logrus.ErrorLevel))
+
+	policy := StackTracePolicy{Enabled: true, Level: logrus.ErrorLevel}
+	require.True(t, policy.appliesTo(logrus.PanicLevel))
+	require.True(t, policy.appliesTo(logrus.ErrorLevel))
+	require.False(t, policy.appliesTo(logrus.WarnLevel))
+
+	require.EqualValues(t, DefaultStackTraceDepth, policy.maxDepth())
+	policy.MaxDepth = 2
+	require.EqualValues(t, 2, policy.maxDepth())
+}
+
+func stackTraceHelper(policy StackTracePolicy) string {
+	return captureStack(0, policy)
+}
+
+func TestCaptureStack(t *testing.T) {
+	stack := stackTraceHelper(StackTracePolicy{})
+	lines := strings.Split(stack, "\n")
+	require.EqualValues(t, "gopkg.in/speijnik/logrus-modular.v1.stackTraceHelper", lines[0])
+	require.Contains(t, lines[1], "stacktrace_test.go:")
+	require.EqualValues(t, "gopkg.in/speijnik/logrus-modular.v1.TestCaptureStack", lines[2])
+	require.NotContains(t, stack, "runtime.")
+
+	stack = stackTraceHelper(StackTracePolicy{Format: StackTraceFormatCompact})
+	require.True(t, strings.HasPrefix(stack, "logrus-modular.v1.stackTraceHelper stacktrace_test.go:"))
+	require.NotContains(t, stack, "\n")
+
+	// Truncated stack traces are marked
+	stack = stackTraceHelper(StackTracePolicy{Format: StackTraceFormatCompact, MaxDepth: 1})
+	frames := strings.Split(stack, "; ")
+	require.Len(t, frames, 2)
+	require.EqualValues(t, "...", frames[1])
+}
+
+func TestLoggerBase_StackTrace(t *testing.T) {
+	buffer := bytes.NewBufferString("")
+	logger := logrus.New()
+	logger.Out = buffer
+	logger.Formatter = &logrus.JSONFormatter{}
+	rl := NewRootLogger(logger)
+
+	core := rl.GetOrCreateChild("core", logrus.InfoLevel)
+	core.SetStackTracePolicy(StackTracePolicy{Enabled: true, Level: logrus.ErrorLevel, Format: StackTraceFormatCompact, MaxDepth: 1})
+
+	read := func() map[string]interface{} {
+		var entry map[string]interface{}
+		require.NoError(t, json.Unmarshal(buffer.Bytes(), &entry))
+		buffer.Reset()
+		return entry
+	}
+
+	// The stack trace starts at the call site for every method
+	for _, fn := range []func(){
+		func() { core.Error("test") },
+		func() { core.Errorf("test") },
+		func() { core.Errorln("test") },
+		func() { core.WithField("key", "value").Error("test") },
+	} {
+		fn()
+		entry := read()
+		require.Contains(t, entry, StackTraceField)
+		require.True(t, strings.HasPrefix(entry[StackTraceField].(string), "logrus-modular.v1.TestLoggerBase_StackTrace.func"), entry[StackTraceField])
+	}
+
+	// Below the threshold
+	core.Warn("test")
+	require.NotContains(t, read(), StackTraceField)
+}
@@ -0,0 +1,75 @@
+package modular
+
+import (
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Stats holds a snapshot of the entry counters of a module, by level, each entry being counted once.
+// Suppressed counts entries below the effective level or the level of the underlying logrus.Logger.
+// Entries kept by a flight recorder are not counted until they are either written, counting as emitted,
+// or overwritten, discarded along with the recorder or below the level of the logrus.Logger when written,
+// counting as dropped.
+type Stats struct {
+	Emitted    map[logrus.Level]uint64
+	Suppressed map[logrus.Level]uint64
+	Dropped    map[logrus.Level]uint64
+}
+
+// moduleStats holds the entry counters of a module, indexed by level
+type moduleStats struct {
+	emitted    [logrus.TraceLevel + 1]uint64
+	suppressed [logrus.TraceLevel + 1]uint64
+	dropped    [logrus.TraceLevel + 1]uint64
+}
+
+func (ms *moduleStats) incEmitted(level logrus.Level) {
+	if ms != nil && level <= logrus.TraceLevel {
+		atomic.AddUint64(&ms.emitted[level], 1)
+	}
+}
+
+func (ms *moduleStats) incSuppressed(level logrus.Level) {
+	if ms != nil && level <= logrus.TraceLevel {
+		atomic.AddUint64(&ms.suppressed[level], 1)
+	}
+}
+
+func (ms *moduleStats) incDropped(level logrus.Level) {
+	if ms != nil && level <= logrus.TraceLevel {
+		atomic.AddUint64(&ms.dropped[level], 1)
+	}
+}
+
+// snapshot returns the current counters, including zero counters for all levels
+func (ms *moduleStats) snapshot() Stats {
+	stats := Stats{
+		Emitted:    make(map[logrus.Level]uint64, len(logrus.AllLevels)),
+		Suppressed: make(map[logrus.Level]uint64, len(logrus.AllLevels)),
+		Dropped:    make(map[logrus.Level]uint64, len(logrus.AllLevels)),
+	}
+	for _, level := range logrus.AllLevels {
+		stats.Emitted[level] = atomic.LoadUint64(&ms.emitted[level])
+		stats.Suppressed[level] = atomic.LoadUint64(&ms.suppressed[level])
+		stats.Dropped[level] = atomic.LoadUint64(&ms.dropped[level])
+	}
+	return stats
+}
+
+func (lm *loggerModule) Stats() Stats {
+	return lm.stats.snapshot()
+}
+
+// getStats returns the module's counters
+func (lm *loggerModule) getStats() *moduleStats {
+	return &lm.stats
+}
+
+// statsOf returns the counters of the given module, if any
+func statsOf(moduleLogger ModuleLogger) *moduleStats {
+	if lm, ok := moduleLogger.(interface{ getStats() *moduleStats }); ok {
+		return lm.getStats()
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package modular
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerModule_Stats(t *testing.T) {
+	logger := logrus.New()
+	logger.Out = bytes.NewBufferString("")
+	rl := NewRootLogger(logger)
+	logger.SetLevel(logrus.WarnLevel)
+
+	db := rl.GetOrCreateChild("db", logrus.InfoLevel)
+	stats := db.Stats()
+	for _, level := range logrus.AllLevels {
+		require.Contains(t, stats.Emitted, level)
+		require.EqualValues(t, 0, stats.Emitted[level])
+	}
+
+	db.Error("error")
+	db.Errorf("error")
+	db.Warn("warn")
+	db.Debug("debug")
+	// Suppressed by the logrus.Logger's level
+	db.Info("info")
+
+	stats = db.Stats()
+	require.EqualValues(t, 2, stats.Emitted[logrus.ErrorLevel])
+	require.EqualValues(t, 1, stats.Emitted[logrus.WarnLevel])
+	require.EqualValues(t, 0, stats.Emitted[logrus.InfoLevel])
+	require.EqualValues(t, 1, stats.Suppressed[logrus.DebugLevel])
+	require.EqualValues(t, 1, stats.Suppressed[logrus.InfoLevel])
+	require.EqualValues(t, 0, stats.Dropped[logrus.DebugLevel])
+
+	// Counters are kept per module
+	require.EqualValues(t, 0, rl.Stats().Emitted[logrus.ErrorLevel])
+}
+
+func TestLoggerModule_Stats_FlightRecorder(t *testing.T) {
+	logger := logrus.New()
+	logger.Out = bytes.NewBufferString("")
+	rl, err := New(logger, WithLevel(logrus.InfoLevel))
+	require.NoError(t, err)
+	logger.SetLevel(logrus.TraceLevel)
+
+	db := rl.GetOrCreateChild("db", logrus.InfoLevel)
+	query := db.GetOrCreateChild("query", logrus.InfoLevel)
+	require.NoError(t, db.SetFlightRecorder(2))
+
+	query.Debug("1")
+	query.Debug("2")
+	query.Debug("3")
+	// Recorded entries are counted once they are written or dropped
+	require.EqualValues(t, 0, query.Stats().Suppressed[logrus.DebugLevel])
+	require.EqualValues(t, 1, query.Stats().Dropped[logrus.DebugLevel])
+
+	query.Error("failed")
+	require.EqualValues(t, 2, query.Stats().Emitted[logrus.DebugLevel])
+	require.EqualValues(t, 1, query.Stats().Emitted[logrus.ErrorLevel])
+	require.EqualValues(t, 0, db.Stats().Emitted[logrus.DebugLevel])
+
+	// Entries are dropped along with the recorder
+	query.Debug("4")
+	require.NoError(t, db.SetFlightRecorder(0))
+	query.Error("failed")
+	require.EqualValues(t, 2, query.Stats().Emitted[logrus.DebugLevel])
+	require.EqualValues(t, 2, query.Stats().Dropped[logrus.DebugLevel])
+
+	// Without a recorder, entries count as suppressed
+	query.Debug("5")
+	require.EqualValues(t, 1, query.Stats().Suppressed[logrus.DebugLevel])
+}